@@ -0,0 +1,269 @@
+package barista
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// PrepTimes is how long each drink and condiment takes to make in a Tall, keyed by name like a
+// beverage.PriceTable. Bigger sizes take a bit longer, and condiments take their time once per
+// serving.
+type PrepTimes map[string]time.Duration
+
+var sizeFactor = map[beverage.Size]float64{
+	beverage.Tall:   1,
+	beverage.Grande: 1.15,
+	beverage.Venti:  1.3,
+}
+
+func DefaultPrepTimes() PrepTimes {
+	return PrepTimes{
+		"Espresso":     45 * time.Second,
+		"House Blend":  30 * time.Second,
+		"Dark Roast":   30 * time.Second,
+		"Decaf":        30 * time.Second,
+		"Mocha":        10 * time.Second,
+		"Soy":          20 * time.Second,
+		"Whip":         5 * time.Second,
+		"Steamed Milk": 40 * time.Second,
+	}
+}
+
+// For works out how long a drink takes to make
+func (p PrepTimes) For(b beverage.Beverage) time.Duration {
+	var d time.Duration
+	for _, item := range b.LineItems() {
+		d += time.Duration(float64(p[item.Name]) * sizeFactor[item.Size] * float64(item.Quantity))
+	}
+	return d
+}
+
+type Status int
+
+const (
+	Queued Status = iota
+	Preparing
+	Ready
+	Cancelled
+)
+
+func (s Status) String() string {
+	switch s {
+	case Queued:
+		return "queued"
+	case Preparing:
+		return "preparing"
+	case Ready:
+		return "ready"
+	case Cancelled:
+		return "cancelled"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+var (
+	ErrShutdown      = errors.New("the shop is closed")
+	ErrNotCancelable = errors.New("order is already being made")
+)
+
+// Ticket follows an order through the queue
+type Ticket struct {
+	ID     int
+	Drinks []beverage.Beverage
+
+	mu        sync.Mutex
+	status    Status
+	submitted time.Time
+	done      chan struct{}
+}
+
+func (t *Ticket) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// Done is closed once the order is ready or cancelled
+func (t *Ticket) Done() <-chan struct{} {
+	return t.done
+}
+
+// Stats is how the shop is keeping up. Times are shop time, not wall clock time (see NewQueue).
+type Stats struct {
+	Queued     int
+	Preparing  int
+	Completed  int
+	Cancelled  int
+	AvgWait    time.Duration
+	MaxWait    time.Duration
+	Throughput float64 // Orders completed per shop hour
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d queued, %d preparing, %d completed, %d cancelled, waits avg %s max %s, %.1f orders/hour",
+		s.Queued, s.Preparing, s.Completed, s.Cancelled, s.AvgWait.Round(time.Second), s.MaxWait.Round(time.Second), s.Throughput)
+}
+
+// Queue is the line of orders waiting for a barista. Orders are made in the order they came in,
+// by however many baristas are working.
+type Queue struct {
+	times   PrepTimes
+	speedup float64
+	opened  time.Time
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*Ticket
+	closed  bool
+	nextID  int
+
+	preparing, completed, cancelled int
+	totalWait, maxWait              time.Duration
+
+	workers sync.WaitGroup
+}
+
+// NewQueue opens the shop with a number of baristas. Shop time runs speedup times faster than
+// the wall clock, so a simulated morning rush doesn't take all morning - with a speedup of 60 a
+// 45 second espresso is made in under a second. It takes at least one barista, and a speedup
+// above zero.
+func NewQueue(baristas int, times PrepTimes, speedup float64) (*Queue, error) {
+	if baristas < 1 {
+		return nil, fmt.Errorf("the shop needs at least one barista, not %d", baristas)
+	}
+	if !(speedup > 0) {
+		return nil, fmt.Errorf("shop time has to run forwards, a speedup of %g won't do", speedup)
+	}
+	q := &Queue{times: times, speedup: speedup, opened: time.Now()}
+	q.cond = sync.NewCond(&q.mu)
+	for i := 0; i < baristas; i++ {
+		q.workers.Add(1)
+		go q.barista()
+	}
+	return q, nil
+}
+
+// Submit puts an order in the queue
+func (q *Queue) Submit(drinks ...beverage.Beverage) (*Ticket, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrShutdown
+	}
+	q.nextID++
+	t := &Ticket{ID: q.nextID, Drinks: drinks, submitted: time.Now(), done: make(chan struct{})}
+	q.pending = append(q.pending, t)
+	q.cond.Signal()
+	return t, nil
+}
+
+// Cancel takes an order out of the queue, as long as no barista has started on it yet
+func (q *Queue) Cancel(id int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, t := range q.pending {
+		if t.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.cancelled++
+			t.finish(Cancelled)
+			return nil
+		}
+	}
+	return fmt.Errorf("order %d: %w", id, ErrNotCancelable)
+}
+
+// Shutdown stops taking orders and waits for the baristas to make the ones already in the queue.
+// If ctx runs out first the baristas are left to it, and ctx's error is returned.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := Stats{
+		Queued:    len(q.pending),
+		Preparing: q.preparing,
+		Completed: q.completed,
+		Cancelled: q.cancelled,
+		MaxWait:   q.maxWait,
+	}
+	if started := q.completed + q.preparing; started > 0 {
+		s.AvgWait = q.totalWait / time.Duration(started)
+	}
+	if open := q.shopTime(time.Since(q.opened)); open > 0 {
+		s.Throughput = float64(q.completed) / open.Hours()
+	}
+	return s
+}
+
+func (q *Queue) barista() {
+	defer q.workers.Done()
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		t := q.pending[0]
+		q.pending = q.pending[1:]
+		q.preparing++
+		now := time.Now()
+		wait := q.shopTime(now.Sub(t.submitted))
+		q.totalWait += wait
+		if wait > q.maxWait {
+			q.maxWait = wait
+		}
+		t.mu.Lock()
+		t.status = Preparing
+		t.mu.Unlock()
+		q.mu.Unlock()
+
+		var prep time.Duration
+		for _, b := range t.Drinks {
+			prep += q.times.For(b)
+		}
+		time.Sleep(time.Duration(float64(prep) / q.speedup))
+
+		q.mu.Lock()
+		q.preparing--
+		q.completed++
+		t.finish(Ready)
+		q.mu.Unlock()
+	}
+}
+
+func (q *Queue) shopTime(wall time.Duration) time.Duration {
+	return time.Duration(float64(wall) * q.speedup)
+}
+
+func (t *Ticket) finish(s Status) {
+	t.mu.Lock()
+	t.status = s
+	t.mu.Unlock()
+	close(t.done)
+}
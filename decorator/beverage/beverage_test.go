@@ -0,0 +1,178 @@
+package beverage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testDrinks = []struct {
+	name string
+	make func() Beverage
+}{
+	{"Espresso", Espresso},
+	{"House Blend", HouseBlend},
+	{"Dark Roast", DarkRoast},
+	{"Decaf", Decaf},
+}
+
+var testCondiments = []struct {
+	name string
+	add  Decorator
+}{
+	{"Mocha", Mocha},
+	{"Soy", Soy},
+	{"Whip", Whip},
+	{"Steamed Milk", SteamedMilk},
+}
+
+// sizeSetters are every place a size can be set on a chain of drink, condiment and another
+// condiment: before or after each layer is added, on the outside or further in
+var sizeSetters = []struct {
+	name  string
+	build func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage
+}{
+	{"base before decorating", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		base.SetSize(s)
+		mid := c(base)
+		return []Beverage{base, mid, c2(mid)}
+	}},
+	{"inner before decorating", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		mid := c(base)
+		mid.SetSize(s)
+		return []Beverage{base, mid, c2(mid)}
+	}},
+	{"outer after decorating", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		mid := c(base)
+		outer := c2(mid)
+		outer.SetSize(s)
+		return []Beverage{base, mid, outer}
+	}},
+	{"inner after decorating", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		mid := c(base)
+		outer := c2(mid)
+		mid.SetSize(s)
+		return []Beverage{base, mid, outer}
+	}},
+	{"base after decorating", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		mid := c(base)
+		outer := c2(mid)
+		base.SetSize(s)
+		return []Beverage{base, mid, outer}
+	}},
+	{"resized outer then inner", func(drink func() Beverage, c, c2 Decorator, s Size) []Beverage {
+		base := drink()
+		mid := c(base)
+		outer := c2(mid)
+		outer.SetSize(Venti - s)
+		mid.SetSize(s)
+		return []Beverage{base, mid, outer}
+	}},
+}
+
+func TestEveryCombination(t *testing.T) {
+	prices := DefaultPrices()
+	for _, d := range testDrinks {
+		for i, c := range testCondiments {
+			// A second, different condiment on top, so there's an inner layer to size too
+			c2 := testCondiments[(i+1)%len(testCondiments)]
+			for _, setter := range sizeSetters {
+				for _, s := range Sizes {
+					t.Run(d.name+"/"+c.name+"/"+setter.name+"/"+s.String(), func(t *testing.T) {
+						layers := setter.build(d.make, c.add, c2.add, s)
+						names := []string{d.name, c.name, c2.name}
+						var want float64
+						for l, b := range layers {
+							want += prices.Price(names[l], s)
+							if got := b.GetSize(); got != s {
+								t.Errorf("layer %d (%s) is %s, want %s", l, names[l], got, s)
+							}
+							if got := b.Cost(); math.Abs(got-want) > 1e-9 {
+								t.Errorf("layer %d (%s) costs %.2f, want %.2f", l, names[l], got, want)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// DarkRoast once had value receivers, so a size set on it was set on a copy and lost
+func TestDarkRoastKeepsItsSize(t *testing.T) {
+	b := DarkRoast()
+	b.SetSize(Venti)
+	if got := b.GetSize(); got != Venti {
+		t.Fatalf("DarkRoast is %s after being made a Venti", got)
+	}
+	if got := b.Cost(); math.Abs(got-1.39) > 1e-9 {
+		t.Errorf("Venti DarkRoast costs %.2f, want 1.39", got)
+	}
+	m := Mocha(b)
+	m.SetSize(Grande)
+	if b.GetSize() != Grande || m.GetSize() != Grande {
+		t.Errorf("sizing a Mocha on a DarkRoast left it %s, and the Mocha %s", b.GetSize(), m.GetSize())
+	}
+	if got := m.Cost(); math.Abs(got-1.44) > 1e-9 {
+		t.Errorf("Grande DarkRoast, Mocha costs %.2f, want 1.44", got)
+	}
+}
+
+// Every layer of a chain used to price the layers under it again, so each condiment doubled
+// the time it took to price a drink
+func TestLongChainPricesQuickly(t *testing.T) {
+	b := DarkRoast()
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			b = Mocha(b)
+		} else {
+			b = Whip(b)
+		}
+	}
+	start := time.Now()
+	cost := b.Cost()
+	items := b.LineItems()
+	FormatOrder(b)
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Fatalf("pricing 50 condiments took %s", took)
+	}
+	// 25 Mochas, the first two at full price, and 25 Whips
+	want := .99 + 2*.20 + 23*.10 + 25*.10
+	if math.Abs(cost-want) > 1e-9 {
+		t.Errorf("costs %.2f, want %.2f", cost, want)
+	}
+	if len(items) != 51 {
+		t.Errorf("%d line items, want 51", len(items))
+	}
+}
+
+// foreign is a drink from outside the package, which Copy can't copy
+type foreign struct {
+	Beverage
+}
+
+func TestResizeLeavesTheOriginal(t *testing.T) {
+	b := Mocha(DarkRoast())
+	b.SetSize(Venti)
+	smaller, err := Resize(b, Grande)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.GetSize() != Venti || smaller.GetSize() != Grande {
+		t.Errorf("Resize() sizes = %v and %v, want Venti and Grande", b.GetSize(), smaller.GetSize())
+	}
+
+	f := Mocha(foreign{DarkRoast()})
+	f.SetSize(Venti)
+	if _, err := Resize(f, Grande); err == nil {
+		t.Error("Resize() of a foreign drink should fail")
+	}
+	if f.GetSize() != Venti {
+		t.Errorf("Resize() of a foreign drink sized it %v", f.GetSize())
+	}
+}
@@ -0,0 +1,191 @@
+package beverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Decorator is anything that wraps a Beverage in a condiment - Mocha, Whip and friends all fit
+type Decorator func(Beverage) Beverage
+
+// Catalog is a menu: the base drinks and condiments a shop sells, looked up by name (in any case).
+type Catalog struct {
+	drinks     map[string]func() Beverage
+	condiments map[string]Decorator
+	// Names as they appear on the menu, in menu order
+	drinkNames, condimentNames []string
+}
+
+// UnknownItemError is returned when a Catalog is asked for something it doesn't sell
+type UnknownItemError struct {
+	Kind string // "drink" or "condiment"
+	Name string
+}
+
+func (e *UnknownItemError) Error() string {
+	return fmt.Sprintf("unknown %s %q", e.Kind, e.Name)
+}
+
+// Drink makes a fresh (Tall) drink off the menu
+func (c *Catalog) Drink(name string) (Beverage, error) {
+	d, ok := c.drinks[strings.ToLower(name)]
+	if !ok {
+		return nil, &UnknownItemError{Kind: "drink", Name: name}
+	}
+	return d(), nil
+}
+
+// Condiment finds the Decorator for a condiment on the menu
+func (c *Catalog) Condiment(name string) (Decorator, error) {
+	d, ok := c.condiments[strings.ToLower(name)]
+	if !ok {
+		return nil, &UnknownItemError{Kind: "condiment", Name: name}
+	}
+	return d, nil
+}
+
+// Drinks lists the names of the drinks on the menu
+func (c *Catalog) Drinks() []string {
+	return append([]string(nil), c.drinkNames...)
+}
+
+// Condiments lists the names of the condiments on the menu
+func (c *Catalog) Condiments() []string {
+	return append([]string(nil), c.condimentNames...)
+}
+
+func newCatalog() *Catalog {
+	return &Catalog{
+		drinks:     map[string]func() Beverage{},
+		condiments: map[string]Decorator{},
+	}
+}
+
+func (c *Catalog) has(name string) bool {
+	name = strings.ToLower(name)
+	_, isDrink := c.drinks[name]
+	_, isCondiment := c.condiments[name]
+	return isDrink || isCondiment
+}
+
+func (c *Catalog) addDrink(name string, d func() Beverage) {
+	c.drinks[strings.ToLower(name)] = d
+	c.drinkNames = append(c.drinkNames, name)
+}
+
+func (c *Catalog) addCondiment(name string, d Decorator) {
+	c.condiments[strings.ToLower(name)] = d
+	c.condimentNames = append(c.condimentNames, name)
+}
+
+// DefaultCatalog is the menu of built-in drinks and condiments, priced from Prices()
+func DefaultCatalog() *Catalog {
+	c := newCatalog()
+	c.addDrink("Espresso", Espresso)
+	c.addDrink("House Blend", HouseBlend)
+	c.addDrink("Dark Roast", DarkRoast)
+	c.addDrink("Decaf", Decaf)
+	c.addCondiment("Mocha", Mocha)
+	c.addCondiment("Soy", Soy)
+	c.addCondiment("Whip", Whip)
+	c.addCondiment("Steamed Milk", SteamedMilk)
+	return c
+}
+
+// MenuItem is how a drink or condiment is written down in a catalog file. It's priced either
+// per size with Prices, or the same for every size with Price.
+type MenuItem struct {
+	Name   string     `json:"name"`
+	Prices SizePrices `json:"prices,omitempty"`
+	Price  *float64   `json:"price,omitempty"`
+}
+
+type menu struct {
+	Drinks     []MenuItem `json:"drinks"`
+	Condiments []MenuItem `json:"condiments"`
+}
+
+// ReadCatalog decodes a JSON menu such as
+//
+//	{
+//		"drinks": [{"name": "Pumpkin Spice", "prices": {"Tall": 3.25, "Grande": 3.75, "Venti": 4.25}}],
+//		"condiments": [{"name": "Caramel", "price": 0.35}]
+//	}
+//
+// The catalog it makes sells only what's in the file, built-in drinks included.
+func ReadCatalog(r io.Reader) (*Catalog, error) {
+	var m menu
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("reading catalog: %w", err)
+	}
+	// Every item is priced from the one table, so drinks and condiments can't share a name
+	t := PriceTable{}
+	c := newCatalog()
+	for _, item := range m.Drinks {
+		if c.has(item.Name) {
+			return nil, fmt.Errorf("catalog drink: %s is on the menu twice", item.Name)
+		}
+		if err := t.addItem(item); err != nil {
+			return nil, fmt.Errorf("catalog drink: %w", err)
+		}
+		name := item.Name
+		c.addDrink(name, func() Beverage {
+			return newDrink(name, t, func(d drink) Beverage { return &d })
+		})
+	}
+	for _, item := range m.Condiments {
+		if c.has(item.Name) {
+			return nil, fmt.Errorf("catalog condiment: %s is on the menu twice", item.Name)
+		}
+		if err := t.addItem(item); err != nil {
+			return nil, fmt.Errorf("catalog condiment: %w", err)
+		}
+		name := item.Name
+		c.addCondiment(name, func(b Beverage) Beverage {
+			return decorate(b, name, t, func(c condiment) Beverage { return c })
+		})
+	}
+	return c, nil
+}
+
+// LoadCatalog reads a JSON menu from a file, see ReadCatalog
+func LoadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := ReadCatalog(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (t PriceTable) addItem(item MenuItem) error {
+	if item.Name == "" {
+		return fmt.Errorf("item has no name")
+	}
+	sp := SizePrices{}
+	switch {
+	case item.Price != nil && item.Prices != nil:
+		return fmt.Errorf("%s has both a price and per-size prices", item.Name)
+	case item.Price != nil:
+		for _, s := range Sizes {
+			sp[s] = *item.Price
+		}
+	default:
+		for _, s := range Sizes {
+			p, ok := item.Prices[s]
+			if !ok {
+				return fmt.Errorf("%s has no %s price", item.Name, s)
+			}
+			sp[s] = p
+		}
+	}
+	t[item.Name] = sp
+	return nil
+}
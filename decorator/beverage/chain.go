@@ -0,0 +1,135 @@
+package beverage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unwrap peels the condiment off, leaving the Beverage it was added to
+func (c condiment) Unwrap() Beverage {
+	return c.Beverage
+}
+
+// layers lists the condiments on b, outermost first
+func layers(b Beverage) []condiment {
+	var cs []condiment
+	for {
+		top, ok := b.(interface{ layer() condiment })
+		if !ok {
+			return cs
+		}
+		cs = append(cs, top.layer())
+		b = top.layer().Beverage
+	}
+}
+
+// copyBase copies the drink at the bottom of a chain, if it's one of ours
+func copyBase(b Beverage) (drink, bool) {
+	d, ok := Base(b).(interface{ copyDrink() drink })
+	if !ok {
+		return drink{}, false
+	}
+	return d.copyDrink(), true
+}
+
+// Base finds the drink at the bottom of a chain of condiments
+func Base(b Beverage) Beverage {
+	for {
+		u, ok := b.(interface{ Unwrap() Beverage })
+		if !ok {
+			return b
+		}
+		b = u.Unwrap()
+	}
+}
+
+// Condiments lists what's been added to a Beverage, in the order it was added
+func Condiments(b Beverage) []LineItem {
+	items := b.LineItems()
+	items = items[:len(items)-1]
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items
+}
+
+// Has checks if a condiment (by name, in any case) has been added to a Beverage
+func Has(b Beverage, name string) bool {
+	for _, c := range layers(b) {
+		if strings.EqualFold(c.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove takes every bit of a condiment (by name, in any case) off a Beverage. Everything else
+// stays in the order it was added, and on the same base drink, so the size carries over.
+func Remove(b Beverage, name string) Beverage {
+	return Replace(b, name, nil)
+}
+
+// Replace swaps a condiment (by name, in any case) for another one, in the same spot and in the
+// same quantity. A nil Decorator just removes it. The new chain is built on a copy of the base
+// drink, so b is left as it was - as long as the base is one of this package's drinks. A base
+// from anywhere else can't be copied, so the new chain shares it, and resizing one resizes both.
+func Replace(b Beverage, name string, d Decorator) Beverage {
+	cs := layers(b)
+	rebuilt := Base(b)
+	if base, ok := copyBase(b); ok {
+		rebuilt = base.wrap(base)
+	}
+	for i := len(cs) - 1; i >= 0; i-- {
+		c := cs[i]
+		add := Decorator(func(b Beverage) Beverage {
+			return decorate(b, c.name, c.prices, c.wrap)
+		})
+		if strings.EqualFold(c.name, name) {
+			add = d
+		}
+		if add != nil {
+			rebuilt = Times(add, c.quantity)(rebuilt)
+		}
+	}
+	return rebuilt
+}
+
+// Reprice copies a Beverage, priced from a different table. The copy is built on a drink of its
+// own, so sizing one doesn't size the other.
+func Reprice(b Beverage, t PriceTable) Beverage {
+	d, ok := copyBase(b)
+	if !ok {
+		base := Base(b)
+		d = drink{name: base.Description(), size: base.GetSize(), wrap: func(d drink) Beverage { return &d }}
+	}
+	d.prices = t
+	rebuilt := d.wrap(d)
+	cs := layers(b)
+	for i := len(cs) - 1; i >= 0; i-- {
+		c := cs[i]
+		add := Decorator(func(b Beverage) Beverage {
+			return decorate(b, c.name, t, c.wrap)
+		})
+		rebuilt = Times(add, c.quantity)(rebuilt)
+	}
+	return rebuilt
+}
+
+// Copy builds a Beverage again on a copy of its base drink, so the copy can be resized (or
+// anything else) without touching the original. Like Replace, it shares a base drink that isn't
+// one of this package's; Resize won't.
+func Copy(b Beverage) Beverage {
+	// No condiment is called "", so nothing is replaced
+	return Replace(b, "", nil)
+}
+
+// Resize copies a Beverage at another size, leaving b as it was. It returns an error for a drink
+// whose base can't be copied, rather than resizing b along with the copy.
+func Resize(b Beverage, size Size) (Beverage, error) {
+	if _, ok := copyBase(b); !ok {
+		return nil, fmt.Errorf("%s can't be copied to resize", Base(b).Description())
+	}
+	c := Copy(b)
+	c.SetSize(size)
+	return c, nil
+}
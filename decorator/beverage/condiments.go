@@ -1,76 +1,128 @@
 package beverage
 
-func Mocha(b Beverage) Beverage {
-	return mocha{Beverage: b}
-}
-
-type mocha struct{
+// condiment is what every decorator is made of. GetSize and SetSize come straight from the
+// wrapped Beverage, so it doesn't matter whether a size is set before or after a condiment is
+// added, or on which layer of the chain it's set - it always lands on the drink underneath.
+type condiment struct {
 	Beverage
+	name     string
+	prices   PriceTable
+	quantity int
+	// wrap turns the condiment back into its own type (mocha, whip, ...) when it's rebuilt
+	wrap func(condiment) Beverage
 }
 
-func (m mocha) Description() string {
-	return m.Beverage.Description() + ", Mocha"
+func (c condiment) layer() condiment {
+	return c
 }
 
-func (m mocha) Cost() float64 {
-	return m.Beverage.Cost() + .20
+// decorate wraps b in a condiment. Adding the same condiment twice in a row doesn't make a new
+// layer, it bumps the quantity of the one that's already on top - Mocha(Mocha(e)) is one layer
+// of Double Mocha.
+func decorate(b Beverage, name string, prices PriceTable, wrap func(condiment) Beverage) Beverage {
+	c := condiment{Beverage: b, name: name, prices: prices, quantity: 1, wrap: wrap}
+	if top, ok := b.(interface{ layer() condiment }); ok && top.layer().name == name {
+		c.Beverage = top.layer().Beverage
+		c.quantity += top.layer().quantity
+	}
+	return wrap(c)
+}
+
+// Times adds n of a condiment at once, e.g. Times(Mocha, 3)(e) for a triple mocha. Condiments
+// are added once and their quantity set, so a big n doesn't make a long chain.
+func Times(d Decorator, n int) Decorator {
+	return func(b Beverage) Beverage {
+		if n < 1 {
+			return b
+		}
+		b = d(b)
+		if top, ok := b.(interface{ layer() condiment }); ok {
+			c := top.layer()
+			c.quantity += n - 1
+			return c.wrap(c)
+		}
+		// Some other kind of decorator, that has to be added one at a time
+		for i := 1; i < n; i++ {
+			b = d(b)
+		}
+		return b
+	}
 }
 
-func Soy(b Beverage) Beverage {
-	return soy{Beverage: b}
+func (c condiment) Description() string {
+	return c.Beverage.Description() + ", " + quantityName(c.name, c.quantity)
 }
 
-type soy struct{
-	Beverage
+func (c condiment) Cost() float64 {
+	items, under := c.priced()
+	cost := under.Cost()
+	for i := len(items) - 1; i >= 0; i-- {
+		cost += items[i].Total()
+	}
+	return cost
 }
 
-func (s soy) Description() string {
-	return s.Beverage.Description() + ", Soy"
+func (c condiment) LineItems() []LineItem {
+	items, under := c.priced()
+	return append(items, under.LineItems()...)
 }
 
-func (s soy) Cost() float64 {
-	var c float64
-	size := s.Beverage.GetSize()
-	switch size {
-	case Tall:
-		c = .1
-	case Grande:
-		c = .15
-	case Venti:
-		c = .2
+// priced prices every condiment layer from c down, returning their line items (outermost first)
+// and whatever's underneath them. Quantity pricing counts every unit of a condiment in the
+// drink, so the layers are priced in one pass from the inside out with a running count of each
+// condiment - a layer never has to look back down the chain itself.
+func (c condiment) priced() ([]LineItem, Beverage) {
+	cs := layers(c)
+	under := cs[len(cs)-1].Beverage
+	counts := map[string]int{}
+	for _, i := range under.LineItems() {
+		counts[i.Name] += i.Quantity
+	}
+	items := make([]LineItem, len(cs))
+	for i := len(cs) - 1; i >= 0; i-- {
+		items[i] = cs[i].item(counts[cs[i].name])
+		counts[cs[i].name] += cs[i].quantity
 	}
-	return s.Beverage.Cost() + c
+	return items, under
 }
 
-func Whip(b Beverage) Beverage {
-	return whip{Beverage: b}
+// item prices the condiment, given how many units of it are already in the drink underneath
+func (c condiment) item(before int) LineItem {
+	size := c.GetSize()
+	item := LineItem{Name: c.name, Size: size, UnitPrice: priceFrom(c.prices, c.name, size), Quantity: c.quantity}
+	units := QuantityRules()[c.name].Units(before+1, before+c.quantity)
+	item.Discount = item.UnitPrice * (float64(c.quantity) - units)
+	return item
 }
 
-type whip struct{
-	Beverage
+func Mocha(b Beverage) Beverage {
+	return decorate(b, "Mocha", nil, func(c condiment) Beverage { return mocha{c} })
 }
 
-func (w whip) Description() string {
-	return w.Beverage.Description() + ", Whip"
+type mocha struct {
+	condiment
 }
 
-func (w whip) Cost() float64 {
-	return w.Beverage.Cost() + .10
+func Soy(b Beverage) Beverage {
+	return decorate(b, "Soy", nil, func(c condiment) Beverage { return soy{c} })
 }
 
-func SteamedMilk(b Beverage) Beverage {
-	return steamedMilk{Beverage: b}
+type soy struct {
+	condiment
 }
 
-type steamedMilk struct{
-	Beverage
+func Whip(b Beverage) Beverage {
+	return decorate(b, "Whip", nil, func(c condiment) Beverage { return whip{c} })
 }
 
-func (s steamedMilk) Description() string {
-	return s.Beverage.Description() + ", Steamed Milk"
+type whip struct {
+	condiment
 }
 
-func (s steamedMilk) Cost() float64 {
-	return s.Beverage.Cost() + .10
+func SteamedMilk(b Beverage) Beverage {
+	return decorate(b, "Steamed Milk", nil, func(c condiment) Beverage { return steamedMilk{c} })
 }
 
+type steamedMilk struct {
+	condiment
+}
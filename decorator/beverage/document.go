@@ -0,0 +1,84 @@
+package beverage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DocumentVersion is the version of Document that Marshal writes. Bump it when a field is added
+// that older readers can't safely ignore.
+const DocumentVersion = 1
+
+// Document is a Beverage written down: its base drink, its size and its condiments in the order
+// they were added. It's what Marshal and Unmarshal turn a Beverage into and back out of.
+type Document struct {
+	Version    int                 `json:"version"`
+	Base       string              `json:"base"`
+	Size       Size                `json:"size"`
+	Condiments []DocumentCondiment `json:"condiments,omitempty"`
+}
+
+type DocumentCondiment struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// NewDocument writes down a Beverage
+func NewDocument(b Beverage) Document {
+	d := Document{
+		Version: DocumentVersion,
+		Base:    Base(b).Description(),
+		Size:    b.GetSize(),
+	}
+	for _, c := range Condiments(b) {
+		d.Condiments = append(d.Condiments, DocumentCondiment{Name: c.Name, Quantity: c.Quantity})
+	}
+	return d
+}
+
+// Beverage makes the drink a Document describes, off a menu
+func (c *Catalog) Beverage(d Document) (Beverage, error) {
+	if d.Version < 1 || d.Version > DocumentVersion {
+		return nil, fmt.Errorf("can't read beverage document version %d, only up to %d", d.Version, DocumentVersion)
+	}
+	if len(d.Condiments) > MaxCondiments {
+		return nil, fmt.Errorf("can't have more than %d condiments, not %d", MaxCondiments, len(d.Condiments))
+	}
+	b, err := c.Drink(d.Base)
+	if err != nil {
+		return nil, err
+	}
+	b.SetSize(d.Size)
+	for _, dc := range d.Condiments {
+		add, err := c.Condiment(dc.Name)
+		if err != nil {
+			return nil, err
+		}
+		if dc.Quantity < 1 || dc.Quantity > MaxQuantity {
+			return nil, fmt.Errorf("can't have %d %s", dc.Quantity, dc.Name)
+		}
+		b = Times(add, dc.Quantity)(b)
+	}
+	return b, nil
+}
+
+// Marshal writes a Beverage out as a JSON Document, e.g.
+//
+//	{"version":1,"base":"Dark Roast","size":"Venti","condiments":[{"name":"Mocha","quantity":2}]}
+func Marshal(b Beverage) ([]byte, error) {
+	return json.Marshal(NewDocument(b))
+}
+
+// Unmarshal reads a Beverage back out of a JSON Document, off the default menu
+func Unmarshal(data []byte) (Beverage, error) {
+	return DefaultCatalog().Unmarshal(data)
+}
+
+// Unmarshal reads a Beverage back out of a JSON Document, off this menu
+func (c *Catalog) Unmarshal(data []byte) (Beverage, error) {
+	var d Document
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("reading beverage document: %w", err)
+	}
+	return c.Beverage(d)
+}
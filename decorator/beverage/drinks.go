@@ -1,98 +1,72 @@
 package beverage
 
-func Espresso() Beverage {
-	return &espresso{}
-}
-
-type espresso struct{
-	size Size
+// drink is what every base drink is made of: a name to describe (and price) it by, and the size
+// of the cup. Drinks off a loaded Catalog bring their own prices, the built-in ones use Prices().
+// Decorators don't keep a size of their own, they ask the drink at the bottom of the chain, so
+// the size only ever lives here. The pointer receivers matter - a drink handed out by value
+// would quietly forget any size it was given.
+type drink struct {
+	name   string
+	size   Size
+	prices PriceTable
+	// wrap turns a copy of the drink back into its own type (espresso, decaf, ...)
+	wrap func(drink) Beverage
 }
 
-func (e espresso) GetSize() Size {
-	return e.size
+// newDrink makes a Tall drink, wrapped up as its own type
+func newDrink(name string, prices PriceTable, wrap func(drink) Beverage) Beverage {
+	return wrap(drink{name: name, prices: prices, wrap: wrap})
 }
 
-func (e *espresso) SetSize(s Size) {
-	e.size = s
+// copyDrink copies the drink, so a chain can be rebuilt on it without touching the original
+func (d *drink) copyDrink() drink {
+	return *d
 }
 
-func (e espresso) Description() string {
-	return "Espresso"
+func (d *drink) GetSize() Size {
+	return d.size
 }
 
-func (e espresso) Cost() float64 {
-	return 1.99
+func (d *drink) SetSize(s Size) {
+	d.size = s
 }
 
-func HouseBlend() Beverage {
-	return &houseBlend{}
+func (d *drink) Description() string {
+	return d.name
 }
 
-type houseBlend struct{
-	size Size
+func (d *drink) Cost() float64 {
+	return priceFrom(d.prices, d.name, d.size)
 }
 
-func (h houseBlend) GetSize() Size {
-	return h.size
+func Espresso() Beverage {
+	return newDrink("Espresso", nil, func(d drink) Beverage { return &espresso{d} })
 }
 
-func (h *houseBlend) SetSize(s Size) {
-	h.size = s
+type espresso struct {
+	drink
 }
 
-func (h houseBlend) Description() string {
-	return "House Blend"
+func HouseBlend() Beverage {
+	return newDrink("House Blend", nil, func(d drink) Beverage { return &houseBlend{d} })
 }
 
-func (h houseBlend) Cost() float64 {
-	return .89
+type houseBlend struct {
+	drink
 }
 
 func DarkRoast() Beverage {
-	return darkRoast{}
+	return newDrink("Dark Roast", nil, func(d drink) Beverage { return &darkRoast{d} })
 }
 
-type darkRoast struct{
-	size Size
-}
-
-func (d darkRoast) GetSize() Size {
-	return d.size
-}
-
-func (d darkRoast) SetSize(s Size) {
-	d.size = s
-}
-
-func (d darkRoast) Description() string {
-	return "Dark Roast"
-}
-
-func (d darkRoast) Cost() float64 {
-	return .99
+type darkRoast struct {
+	drink
 }
 
 func Decaf() Beverage {
-	return &decaf{}
-}
-
-type decaf struct{
-	size Size
-}
-
-func (d decaf) GetSize() Size {
-	return d.size
-}
-
-func (d *decaf) SetSize(s Size) {
-	d.size = s
+	return newDrink("Decaf", nil, func(d drink) Beverage { return &decaf{d} })
 }
 
-func (d decaf) Description() string {
-	return "Decaf"
+type decaf struct {
+	drink
 }
-
-func (d decaf) Cost() float64 {
-	return 1.05
-}
-
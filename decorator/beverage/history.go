@@ -0,0 +1,140 @@
+package beverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// PriceList is a set of prices and the time they take effect. A list only has to mention the
+// items whose price it changes, everything else carries on at its price from the list before.
+type PriceList struct {
+	EffectiveFrom time.Time  `json:"effective_from"`
+	Prices        PriceTable `json:"prices"`
+}
+
+// PriceHistory is every price list there's been, oldest first, so orders can be priced as they
+// were on the day they were placed
+type PriceHistory []PriceList
+
+// NewPriceHistory puts price lists in order. No two lists can take effect at the same time.
+func NewPriceHistory(lists ...PriceList) (PriceHistory, error) {
+	h := append(PriceHistory(nil), lists...)
+	sort.SliceStable(h, func(i, j int) bool { return h[i].EffectiveFrom.Before(h[j].EffectiveFrom) })
+	for i := 1; i < len(h); i++ {
+		if h[i].EffectiveFrom.Equal(h[i-1].EffectiveFrom) {
+			return nil, fmt.Errorf("two price lists take effect at %s", h[i].EffectiveFrom.Format(time.RFC3339))
+		}
+	}
+	return h, nil
+}
+
+// At works out the prices in effect at a time
+func (h PriceHistory) At(t time.Time) (PriceTable, error) {
+	if len(h) == 0 || t.Before(h[0].EffectiveFrom) {
+		return nil, fmt.Errorf("no prices in effect at %s", t.Format(time.RFC3339))
+	}
+	prices := PriceTable{}
+	for _, l := range h {
+		if t.Before(l.EffectiveFrom) {
+			break
+		}
+		for name, sp := range l.Prices {
+			prices[name] = sp
+		}
+	}
+	return prices, nil
+}
+
+// Latest is the prices in effect once every list in the history has taken effect
+func (h PriceHistory) Latest() (PriceTable, error) {
+	if len(h) == 0 {
+		return nil, fmt.Errorf("no prices in the history")
+	}
+	return h.At(h[len(h)-1].EffectiveFrom)
+}
+
+// CostAt is what a Beverage cost (or will cost) at a time
+func (h PriceHistory) CostAt(b Beverage, t time.Time) (float64, error) {
+	prices, err := h.At(t)
+	if err != nil {
+		return 0, err
+	}
+	return Reprice(b, prices).Cost(), nil
+}
+
+// ReadPriceHistory decodes a JSON list of price lists such as
+//
+//	[
+//		{"effective_from": "2020-01-01T00:00:00Z", "prices": {"Espresso": {"Tall": 1.99, "Grande": 2.19, "Venti": 2.39}}},
+//		{"effective_from": "2020-06-01T00:00:00Z", "prices": {"Espresso": {"Tall": 2.09, "Grande": 2.29, "Venti": 2.49}}}
+//	]
+//
+// Every item in a list has to be priced for every size.
+func ReadPriceHistory(r io.Reader) (PriceHistory, error) {
+	var lists []PriceList
+	if err := json.NewDecoder(r).Decode(&lists); err != nil {
+		return nil, fmt.Errorf("reading price history: %w", err)
+	}
+	for _, l := range lists {
+		for name, sp := range l.Prices {
+			for _, s := range Sizes {
+				if _, ok := sp[s]; !ok {
+					return nil, fmt.Errorf("price history: %s has no %s price from %s", name, s, l.EffectiveFrom.Format(time.RFC3339))
+				}
+			}
+		}
+	}
+	return NewPriceHistory(lists...)
+}
+
+// LoadPriceHistory reads a JSON price history from a file, see ReadPriceHistory
+func LoadPriceHistory(path string) (PriceHistory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h, err := ReadPriceHistory(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// PriceChange is one item's price in one size going from one price list to another. Items that
+// are only in one of the lists go from or to zero.
+type PriceChange struct {
+	Name     string
+	Size     Size
+	Old, New float64
+}
+
+// DiffPrices lists every price that's different between two tables, by item then size
+func DiffPrices(old, new PriceTable) []PriceChange {
+	names := map[string]bool{}
+	for name := range old {
+		names[name] = true
+	}
+	for name := range new {
+		names[name] = true
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []PriceChange
+	for _, name := range sorted {
+		for _, s := range Sizes {
+			if o, n := old.Price(name, s), new.Price(name, s); o != n {
+				changes = append(changes, PriceChange{Name: name, Size: s, Old: o, New: n})
+			}
+		}
+	}
+	return changes
+}
@@ -1,15 +1,70 @@
 package beverage
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Beverage interface {
 	Description() string
 	Cost() float64
 	GetSize() Size
 	SetSize(s Size)
+	// LineItems breaks the Beverage down into what went into it, starting with the outermost
+	// condiment and ending with the base drink
+	LineItems() []LineItem
+	// Nutrition and Allergens add up everything in the Beverage, base drink and condiments
+	Nutrition() Nutrition
+	Allergens() []Allergen
 }
 
 type Size int
+
 const (
 	Tall Size = iota
 	Grande
 	Venti
 )
+
+// Sizes lists every size, smallest first
+var Sizes = []Size{Tall, Grande, Venti}
+
+var sizeNames = map[Size]string{
+	Tall:   "Tall",
+	Grande: "Grande",
+	Venti:  "Venti",
+}
+
+func (s Size) String() string {
+	if n, ok := sizeNames[s]; ok {
+		return n
+	}
+	return fmt.Sprintf("Size(%d)", int(s))
+}
+
+// MarshalText lets sizes show up by name in JSON (e.g. as price table keys)
+func (s Size) MarshalText() ([]byte, error) {
+	if _, ok := sizeNames[s]; !ok {
+		return nil, fmt.Errorf("unknown size %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *Size) UnmarshalText(text []byte) error {
+	size, err := ParseSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// ParseSize turns a size name like "venti" (any case) back into a Size
+func ParseSize(name string) (Size, error) {
+	for s, n := range sizeNames {
+		if strings.EqualFold(n, name) {
+			return s, nil
+		}
+	}
+	return Tall, fmt.Errorf("unknown size %q", name)
+}
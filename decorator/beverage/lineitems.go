@@ -0,0 +1,41 @@
+package beverage
+
+import "fmt"
+
+// LineItem is one thing that went into a Beverage, as it would show up on a receipt
+type LineItem struct {
+	Name      string
+	Size      Size
+	UnitPrice float64
+	Quantity  int
+	// Discount is taken off the line by quantity pricing, e.g. a half price third mocha
+	Discount float64
+}
+
+// Total is what the line item adds to the cost of the drink
+func (l LineItem) Total() float64 {
+	return l.UnitPrice*float64(l.Quantity) - l.Discount
+}
+
+// String names the line item with its quantity, e.g. "Double Mocha"
+func (l LineItem) String() string {
+	return quantityName(l.Name, l.Quantity)
+}
+
+func (d *drink) LineItems() []LineItem {
+	return []LineItem{{Name: d.name, Size: d.size, UnitPrice: d.Cost(), Quantity: 1}}
+}
+
+func quantityName(name string, n int) string {
+	switch n {
+	case 1:
+		return name
+	case 2:
+		return "Double " + name
+	case 3:
+		return "Triple " + name
+	case 4:
+		return "Quad " + name
+	}
+	return fmt.Sprintf("%s x%d", name, n)
+}
@@ -0,0 +1,109 @@
+package beverage
+
+import (
+	"sort"
+	"sync"
+)
+
+// Nutrition is what's in a drink, or in one serving of a condiment
+type Nutrition struct {
+	Calories float64 // kcal
+	Sugar    float64 // grams
+	Caffeine float64 // milligrams
+}
+
+func (n Nutrition) add(o Nutrition) Nutrition {
+	return Nutrition{Calories: n.Calories + o.Calories, Sugar: n.Sugar + o.Sugar, Caffeine: n.Caffeine + o.Caffeine}
+}
+
+func (n Nutrition) scale(by float64) Nutrition {
+	return Nutrition{Calories: n.Calories * by, Sugar: n.Sugar * by, Caffeine: n.Caffeine * by}
+}
+
+type Allergen string
+
+const (
+	AllergenDairy Allergen = "dairy"
+	AllergenSoy   Allergen = "soy"
+)
+
+// ItemNutrition is the nutrition of a Tall serving of an item, and the allergens in it. Bigger
+// sizes scale up from the Tall serving by how much more cup there is.
+type ItemNutrition struct {
+	Tall      Nutrition
+	Allergens []Allergen
+}
+
+// NutritionTable holds the nutrition of every drink and condiment, keyed by name like PriceTable
+type NutritionTable map[string]ItemNutrition
+
+// sizeScale is how much bigger each cup is than a Tall (12oz, 16oz and 20oz)
+var sizeScale = map[Size]float64{
+	Tall:   1,
+	Grande: 16.0 / 12,
+	Venti:  20.0 / 12,
+}
+
+func DefaultNutrition() NutritionTable {
+	return NutritionTable{
+		"Espresso":     {Tall: Nutrition{Calories: 10, Caffeine: 150}},
+		"House Blend":  {Tall: Nutrition{Calories: 5, Caffeine: 260}},
+		"Dark Roast":   {Tall: Nutrition{Calories: 5, Caffeine: 195}},
+		"Decaf":        {Tall: Nutrition{Calories: 5, Caffeine: 15}},
+		"Mocha":        {Tall: Nutrition{Calories: 70, Sugar: 11, Caffeine: 10}, Allergens: []Allergen{AllergenDairy}},
+		"Soy":          {Tall: Nutrition{Calories: 60, Sugar: 5}, Allergens: []Allergen{AllergenSoy}},
+		"Whip":         {Tall: Nutrition{Calories: 80, Sugar: 6}, Allergens: []Allergen{AllergenDairy}},
+		"Steamed Milk": {Tall: Nutrition{Calories: 100, Sugar: 9}, Allergens: []Allergen{AllergenDairy}},
+	}
+}
+
+var (
+	nutritionMu sync.RWMutex
+	nutrition   = DefaultNutrition()
+)
+
+// NutritionFacts returns the table every beverage in the package reports its nutrition from
+func NutritionFacts() NutritionTable {
+	nutritionMu.RLock()
+	defer nutritionMu.RUnlock()
+	return nutrition
+}
+
+// SetNutritionFacts swaps the table every beverage in the package reports its nutrition from
+func SetNutritionFacts(t NutritionTable) {
+	nutritionMu.Lock()
+	defer nutritionMu.Unlock()
+	nutrition = t
+}
+
+// nutritionOf looks up one serving of an item in a given size. Anything missing from the table
+// is assumed to be nothing but water.
+func nutritionOf(name string, s Size) Nutrition {
+	return NutritionFacts()[name].Tall.scale(sizeScale[s])
+}
+
+func (d *drink) Nutrition() Nutrition {
+	return nutritionOf(d.name, d.size)
+}
+
+func (d *drink) Allergens() []Allergen {
+	return append([]Allergen(nil), NutritionFacts()[d.name].Allergens...)
+}
+
+func (c condiment) Nutrition() Nutrition {
+	return c.Beverage.Nutrition().add(nutritionOf(c.name, c.GetSize()).scale(float64(c.quantity)))
+}
+
+// Allergens lists every allergen in the drink so far, once each, in alphabetical order
+func (c condiment) Allergens() []Allergen {
+	seen := map[Allergen]bool{}
+	var all []Allergen
+	for _, a := range append(c.Beverage.Allergens(), NutritionFacts()[c.name].Allergens...) {
+		if !seen[a] {
+			seen[a] = true
+			all = append(all, a)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all
+}
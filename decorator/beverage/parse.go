@@ -0,0 +1,186 @@
+package beverage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError points at the part of an order that couldn't be made sense of
+type ParseError struct {
+	Text   string // The whole order
+	Word   string // The word (or words) that didn't make sense
+	Offset int    // Where Word starts in Text
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v at offset %d in %q", e.Err, e.Offset, e.Text)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+var quantityWords = map[string]int{
+	"single": 1,
+	"double": 2,
+	"triple": 3,
+	"quad":   4,
+}
+
+// ParseOrder reads an order off the default menu, see Catalog.ParseOrder
+func ParseOrder(text string) (Beverage, error) {
+	return DefaultCatalog().ParseOrder(text)
+}
+
+// ParseOrder turns an order like "venti dark roast, double mocha, soy, whip" into a decorated
+// Beverage. The first part is the drink, optionally starting with its size (Tall if it doesn't),
+// and every part after that is a condiment. Condiments can be asked for more than once with
+// "double", "triple" or "quad" in front, or a count like "x3" on either side.
+func (c *Catalog) ParseOrder(text string) (Beverage, error) {
+	parts := splitWords(text)
+	if len(parts) == 0 || len(parts[0]) == 0 {
+		return nil, &ParseError{Text: text, Err: errors.New("no drink ordered")}
+	}
+
+	if len(parts)-1 > MaxCondiments {
+		return nil, &ParseError{Text: text, Offset: commaOffset(text, MaxCondiments+1), Err: fmt.Errorf("can't have more than %d condiments", MaxCondiments)}
+	}
+	b, err := c.parseDrink(text, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	for i, part := range parts[1:] {
+		if len(part) == 0 {
+			// Point just after the comma that started the empty part
+			return nil, &ParseError{Text: text, Offset: commaOffset(text, i+1) + 1, Err: errors.New("missing condiment")}
+		}
+		d, n, err := c.parseCondiment(text, part)
+		if err != nil {
+			return nil, err
+		}
+		b = Times(d, n)(b)
+	}
+	return b, nil
+}
+
+func (c *Catalog) parseDrink(text string, words []word) (Beverage, error) {
+	size, sized := Tall, false
+	if s, err := ParseSize(words[0].text); err == nil {
+		size, sized = s, true
+		if len(words) == 1 {
+			return nil, &ParseError{Text: text, Word: words[0].text, Offset: words[0].offset, Err: errors.New("no drink ordered")}
+		}
+		words = words[1:]
+	}
+	b, err := c.Drink(joinWords(words))
+	if err != nil {
+		// "vneti dark roast" is more helpfully a bad size than an unknown drink
+		if !sized && len(words) > 1 {
+			if _, err := c.Drink(joinWords(words[1:])); err == nil {
+				return nil, &ParseError{Text: text, Word: words[0].text, Offset: words[0].offset, Err: fmt.Errorf("unknown size %q", words[0].text)}
+			}
+		}
+		return nil, &ParseError{Text: text, Word: joinWords(words), Offset: words[0].offset, Err: err}
+	}
+	b.SetSize(size)
+	return b, nil
+}
+
+func (c *Catalog) parseCondiment(text string, words []word) (Decorator, int, error) {
+	n := 1
+	if len(words) > 1 {
+		var qw word
+		if q, ok := parseQuantity(words[0].text); ok {
+			n, qw, words = q, words[0], words[1:]
+		} else if q, ok := parseQuantity(words[len(words)-1].text); ok {
+			n, qw, words = q, words[len(words)-1], words[:len(words)-1]
+		}
+		if n < 1 {
+			return nil, 0, &ParseError{Text: text, Word: qw.text, Offset: qw.offset, Err: errors.New("condiment quantity has to be at least one")}
+		}
+		if n > MaxQuantity {
+			return nil, 0, &ParseError{Text: text, Word: qw.text, Offset: qw.offset, Err: fmt.Errorf("can't have more than %d of a condiment", MaxQuantity)}
+		}
+	}
+	d, err := c.Condiment(joinWords(words))
+	if err != nil {
+		return nil, 0, &ParseError{Text: text, Word: joinWords(words), Offset: words[0].offset, Err: err}
+	}
+	return d, n, nil
+}
+
+// parseQuantity understands "double", "x3", "3x" and plain "3"
+func parseQuantity(w string) (int, bool) {
+	w = strings.ToLower(w)
+	if n, ok := quantityWords[w]; ok {
+		return n, true
+	}
+	w = strings.TrimSuffix(strings.TrimPrefix(w, "x"), "x")
+	n, err := strconv.Atoi(w)
+	return n, err == nil
+}
+
+// FormatOrder writes a Beverage back out as order text that ParseOrder understands, e.g.
+// "venti dark roast, double mocha, soy, whip".
+func FormatOrder(b Beverage) string {
+	items := b.LineItems()
+	base := items[len(items)-1]
+	parts := []string{strings.ToLower(base.Size.String() + " " + base.Name)}
+	// Condiments go in the order they were added
+	for i := len(items) - 2; i >= 0; i-- {
+		parts = append(parts, strings.ToLower(items[i].String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type word struct {
+	text   string
+	offset int
+}
+
+// splitWords breaks an order into its comma-separated parts, and those into words, remembering
+// where every word started so errors can point at it
+func splitWords(text string) [][]word {
+	parts := [][]word{nil}
+	start := -1
+	for i, r := range text + " " {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == ',':
+			if start >= 0 {
+				last := len(parts) - 1
+				parts[last] = append(parts[last], word{text: text[start:i], offset: start})
+				start = -1
+			}
+			if r == ',' {
+				parts = append(parts, nil)
+			}
+		case start < 0:
+			start = i
+		}
+	}
+	return parts
+}
+
+func joinWords(words []word) string {
+	s := make([]string, len(words))
+	for i, w := range words {
+		s[i] = w.text
+	}
+	return strings.Join(s, " ")
+}
+
+// commaOffset finds the nth comma in text
+func commaOffset(text string, n int) int {
+	for i, r := range text {
+		if r == ',' {
+			n--
+			if n == 0 {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
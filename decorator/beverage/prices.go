@@ -0,0 +1,103 @@
+package beverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// PriceTable holds what every drink and condiment costs in each Size. Items are keyed by the
+// same name they use in their Description, e.g. "Dark Roast" or "Steamed Milk".
+type PriceTable map[string]SizePrices
+
+// SizePrices is the price of a single item for each Size
+type SizePrices map[Size]float64
+
+// Price looks up an item in the table. Unknown items (or sizes) are free, which is about as
+// generous as a coffee shop gets.
+func (t PriceTable) Price(name string, s Size) float64 {
+	return t[name][s]
+}
+
+// DefaultPrices is the menu as it was originally hard-coded; Tall prices are the old flat prices
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"Espresso":     {Tall: 1.99, Grande: 2.19, Venti: 2.39},
+		"House Blend":  {Tall: .89, Grande: 1.09, Venti: 1.29},
+		"Dark Roast":   {Tall: .99, Grande: 1.19, Venti: 1.39},
+		"Decaf":        {Tall: 1.05, Grande: 1.25, Venti: 1.45},
+		"Mocha":        {Tall: .20, Grande: .25, Venti: .30},
+		"Soy":          {Tall: .10, Grande: .15, Venti: .20},
+		"Whip":         {Tall: .10, Grande: .12, Venti: .15},
+		"Steamed Milk": {Tall: .10, Grande: .15, Venti: .20},
+	}
+}
+
+var (
+	pricesMu sync.RWMutex
+	prices   = DefaultPrices()
+)
+
+// Prices returns the table every beverage in the package is currently priced from
+func Prices() PriceTable {
+	pricesMu.RLock()
+	defer pricesMu.RUnlock()
+	return prices
+}
+
+// SetPrices swaps the table every beverage in the package is priced from
+func SetPrices(t PriceTable) {
+	pricesMu.Lock()
+	defer pricesMu.Unlock()
+	prices = t
+}
+
+// priceFrom prices an item from its own table, if it has one, or from the package's table
+func priceFrom(t PriceTable, name string, s Size) float64 {
+	if t == nil {
+		t = Prices()
+	}
+	return t.Price(name, s)
+}
+
+// ReadPriceTable decodes a JSON price table such as
+//
+//	{"Espresso": {"Tall": 1.99, "Grande": 2.19, "Venti": 2.39}}
+//
+// Every item in the table has to be priced for every size.
+func ReadPriceTable(r io.Reader) (PriceTable, error) {
+	var t PriceTable
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("reading price table: %w", err)
+	}
+	for name, sp := range t {
+		for _, s := range Sizes {
+			if _, ok := sp[s]; !ok {
+				return nil, fmt.Errorf("price table: %s has no %s price", name, s)
+			}
+		}
+	}
+	return t, nil
+}
+
+// LoadPrices reads a JSON price table from a file and starts pricing from it. Items the file
+// doesn't mention keep the price they had in the default table.
+func LoadPrices(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	loaded, err := ReadPriceTable(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t := DefaultPrices()
+	for name, sp := range loaded {
+		t[name] = sp
+	}
+	SetPrices(t)
+	return nil
+}
@@ -0,0 +1,86 @@
+package beverage
+
+import (
+	"sort"
+	"sync"
+)
+
+// QuantityRule prices extra units of a condiment. Each tier covers the units from its From
+// onwards (counting from 1) until the next tier starts; units before the first tier pay full price.
+type QuantityRule []QuantityTier
+
+type QuantityTier struct {
+	From       int     `json:"from"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Multiplier is how much of the unit price the nth unit of a condiment costs
+func (r QuantityRule) Multiplier(n int) float64 {
+	m := 1.0
+	for _, t := range r {
+		if n >= t.From {
+			m = t.Multiplier
+		}
+	}
+	return m
+}
+
+// Units adds up the multipliers of the units from one to another (both counting) - what they
+// cost together, in unit prices. The multiplier only changes where a tier starts, so every
+// stretch between those is added up in one go however many units it covers.
+func (r QuantityRule) Units(from, to int) float64 {
+	if to < from {
+		return 0
+	}
+	starts := []int{from}
+	for _, t := range r {
+		if t.From > from && t.From <= to {
+			starts = append(starts, t.From)
+		}
+	}
+	sort.Ints(starts)
+	var units float64
+	for i, start := range starts {
+		end := to
+		if i+1 < len(starts) {
+			end = starts[i+1] - 1
+		}
+		if end >= start {
+			units += float64(end-start+1) * r.Multiplier(start)
+		}
+	}
+	return units
+}
+
+// MaxQuantity is the most of one condiment an order can ask for in one go. Anything more is
+// somebody leaning on the keyboard.
+const MaxQuantity = 10
+
+// MaxCondiments is the most condiments (each in whatever quantity) one drink can be ordered with
+const MaxCondiments = 10
+
+// DefaultQuantityRules has every mocha pump after the second at half price
+func DefaultQuantityRules() map[string]QuantityRule {
+	return map[string]QuantityRule{
+		"Mocha": {{From: 3, Multiplier: .5}},
+	}
+}
+
+var (
+	quantityRulesMu sync.RWMutex
+	quantityRules   = DefaultQuantityRules()
+)
+
+// QuantityRules returns the rules condiments are currently priced with, keyed by condiment name
+func QuantityRules() map[string]QuantityRule {
+	quantityRulesMu.RLock()
+	defer quantityRulesMu.RUnlock()
+	return quantityRules
+}
+
+// SetQuantityRules swaps the rules condiments are priced with
+func SetQuantityRules(r map[string]QuantityRule) {
+	quantityRulesMu.Lock()
+	defer quantityRulesMu.Unlock()
+	quantityRules = r
+}
@@ -0,0 +1,164 @@
+package beverage
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Severity int
+
+const (
+	// Warning is something the barista should raise an eyebrow at, but will still make
+	Warning Severity = iota
+	// Invalid is something that can't be made
+	Invalid
+)
+
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "invalid"
+}
+
+// Violation is one way a Beverage broke a Rule
+type Violation struct {
+	Severity Severity
+	Message  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Severity, v.Message)
+}
+
+// Rule checks a Beverage for combinations that shouldn't (or can't) be made
+type Rule interface {
+	Check(b Beverage) []Violation
+}
+
+// RuleFunc lets a plain function be used as a Rule
+type RuleFunc func(b Beverage) []Violation
+
+func (f RuleFunc) Check(b Beverage) []Violation {
+	return f(b)
+}
+
+// ValidationError is every Invalid violation a Beverage had
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return "invalid beverage: " + strings.Join(msgs, "; ")
+}
+
+// DefaultRules are the house rules: one kind of milk at a time, only so much mocha for the size
+// of the cup, no steamed milk in an espresso, and a raised eyebrow at anything past double whip.
+func DefaultRules() []Rule {
+	return []Rule{
+		ConflictingCondiments("Soy", "Steamed Milk"),
+		MaxPerSize("Mocha", map[Size]int{Tall: 3, Grande: 4, Venti: 5}),
+		NotAllowedOn("Espresso", "Steamed Milk"),
+		AsWarning(MaxPerSize("Whip", map[Size]int{Tall: 2, Grande: 2, Venti: 2})),
+	}
+}
+
+// Validate checks a Beverage against rules. Warnings are handed back for the caller to do with as
+// they please; if anything is Invalid the error is a *ValidationError listing all of it.
+func Validate(b Beverage, rules ...Rule) ([]Violation, error) {
+	var warnings, invalid []Violation
+	for _, r := range rules {
+		for _, v := range r.Check(b) {
+			if v.Severity == Invalid {
+				invalid = append(invalid, v)
+			} else {
+				warnings = append(warnings, v)
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return warnings, &ValidationError{Violations: invalid}
+	}
+	return warnings, nil
+}
+
+// Build adds condiments to a base drink and checks the result against rules, refusing to hand
+// back a Beverage that breaks any of them
+func Build(rules []Rule, base Beverage, condiments ...Decorator) (Beverage, []Violation, error) {
+	b := base
+	for _, c := range condiments {
+		b = c(b)
+	}
+	warnings, err := Validate(b, rules...)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return b, warnings, nil
+}
+
+// ConflictingCondiments doesn't allow more than one of a set of condiments in the same drink
+func ConflictingCondiments(names ...string) Rule {
+	return RuleFunc(func(b Beverage) []Violation {
+		var found []string
+		for _, n := range names {
+			if Has(b, n) {
+				found = append(found, n)
+			}
+		}
+		if len(found) < 2 {
+			return nil
+		}
+		return []Violation{{Severity: Invalid, Message: fmt.Sprintf("%s can't go in the same drink", strings.Join(found, " and "))}}
+	})
+}
+
+// MaxPerSize caps how many of a condiment can go in each size of drink
+func MaxPerSize(name string, max map[Size]int) Rule {
+	return RuleFunc(func(b Beverage) []Violation {
+		size := b.GetSize()
+		limit, ok := max[size]
+		if !ok {
+			return nil
+		}
+		if n := count(b, name); n > limit {
+			return []Violation{{Severity: Invalid, Message: fmt.Sprintf("%d %s is too many for a %s, the most is %d", n, name, size, limit)}}
+		}
+		return nil
+	})
+}
+
+// NotAllowedOn keeps a condiment off one kind of base drink
+func NotAllowedOn(drink, condiment string) Rule {
+	return RuleFunc(func(b Beverage) []Violation {
+		if strings.EqualFold(Base(b).Description(), drink) && Has(b, condiment) {
+			return []Violation{{Severity: Invalid, Message: fmt.Sprintf("%s doesn't go on %s", condiment, drink)}}
+		}
+		return nil
+	})
+}
+
+// AsWarning turns everything a Rule finds into a Warning
+func AsWarning(r Rule) Rule {
+	return RuleFunc(func(b Beverage) []Violation {
+		vs := r.Check(b)
+		for i := range vs {
+			vs[i].Severity = Warning
+		}
+		return vs
+	})
+}
+
+// count adds up how much of a condiment (by name, in any case) is in a Beverage
+func count(b Beverage, name string) int {
+	var n int
+	for _, item := range Condiments(b) {
+		if strings.EqualFold(item.Name, name) {
+			n += item.Quantity
+		}
+	}
+	return n
+}
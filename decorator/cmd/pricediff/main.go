@@ -0,0 +1,75 @@
+// Compares two price lists from a price history, and previews what the change would do to the
+// orders in the order log
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/orderlog"
+	"headfirstdesigntraining/decorator/report"
+)
+
+var (
+	historyFile = flag.String("history", "price-history.json", "JSON price history")
+	oldAt       = flag.String("old", "", "compare the prices in effect on this day, as YYYY-MM-DD (defaults to today)")
+	newAt       = flag.String("new", "", "against the prices in effect on this day, as YYYY-MM-DD (defaults to the latest price list)")
+	logFile     = flag.String("log", "orders.log", "order log to preview the change on")
+	days        = flag.Int("days", 30, "how many days of recent orders to preview the change on")
+)
+
+func main() {
+	flag.Parse()
+	history, err := beverage.LoadPriceHistory(*historyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	old, err := history.At(day(*oldAt, time.Now()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var new beverage.PriceTable
+	if *newAt == "" {
+		new, err = history.Latest()
+	} else {
+		new, err = history.At(day(*newAt, time.Time{}))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	changes := beverage.DiffPrices(old, new)
+	if len(changes) == 0 {
+		fmt.Println("No prices change")
+		return
+	}
+	for _, c := range changes {
+		fmt.Printf("%-14s %-6s %5.2f -> %5.2f\n", c.Name, c.Size, c.Old, c.New)
+	}
+
+	orders, err := orderlog.Open(*logFile).Read(time.Now().AddDate(0, 0, -*days), time.Time{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("\nOn the last %d days of orders:\n", *days)
+	if err := report.Reprice(orders, old, new).WriteTable(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// day reads a YYYY-MM-DD date, taking it as the end of the day so that a price list that took
+// effect that day counts
+func day(s string, otherwise time.Time) time.Time {
+	if s == "" {
+		return otherwise
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		log.Fatalf("bad date %q, dates look like 2020-05-01", s)
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
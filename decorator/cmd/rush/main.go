@@ -0,0 +1,67 @@
+// Simulates the morning rush: a crowd of orders, a few baristas, and how long everyone waits
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"headfirstdesigntraining/decorator/barista"
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+var (
+	baristas = flag.Int("baristas", 3, "number of baristas working")
+	orders   = flag.Int("orders", 40, "number of orders in the rush")
+	speedup  = flag.Float64("speedup", 300, "how much faster than real time the shop runs")
+	arrivals = flag.Duration("arrivals", 20*time.Second, "average (shop) time between customers")
+)
+
+var usuals = []string{
+	"tall house blend",
+	"grande dark roast, steamed milk",
+	"venti dark roast, double mocha, whip",
+	"tall espresso",
+	"grande decaf, soy",
+	"venti house blend, mocha, soy, whip",
+}
+
+func main() {
+	flag.Parse()
+	q, err := barista.NewQueue(*baristas, barista.DefaultPrepTimes(), *speedup)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i := 0; i < *orders; i++ {
+		b, err := beverage.ParseOrder(usuals[rand.Intn(len(usuals))])
+		if err != nil {
+			log.Fatal(err)
+		}
+		t, err := q.Submit(b)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// Every so often someone gets tired of waiting and walks out
+		if rand.Intn(10) == 0 {
+			if err := q.Cancel(t.ID); err == nil {
+				fmt.Printf("Order %d walked out\n", t.ID)
+			}
+		}
+		if i%10 == 0 {
+			fmt.Println(q.Stats())
+		}
+		gap := time.Duration(rand.ExpFloat64() * float64(*arrivals))
+		time.Sleep(time.Duration(float64(gap) / *speedup))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := q.Shutdown(ctx); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Closing time:", q.Stats())
+}
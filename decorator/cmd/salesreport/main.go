@@ -0,0 +1,57 @@
+// Sales figures from the order log: revenue by drink, condiment attach rates, size mix and
+// sales by hour of the day
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"headfirstdesigntraining/decorator/orderlog"
+	"headfirstdesigntraining/decorator/report"
+)
+
+var (
+	logFile = flag.String("log", "orders.log", "order log to report on")
+	from    = flag.String("from", "", "first day to report on, as YYYY-MM-DD (defaults to the start of the log)")
+	to      = flag.String("to", "", "last day to report on, as YYYY-MM-DD (defaults to the end of the log)")
+	format  = flag.String("format", "table", "table or csv")
+)
+
+func main() {
+	flag.Parse()
+	var start, end time.Time
+	if *from != "" {
+		start = day(*from)
+	}
+	if *to != "" {
+		// The last day counts, so report up until the start of the next one
+		end = day(*to).AddDate(0, 0, 1)
+	}
+
+	orders, err := orderlog.Open(*logFile).Read(start, end)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := report.Build(orders)
+	switch *format {
+	case "table":
+		err = r.WriteTable(os.Stdout)
+	case "csv":
+		err = r.WriteCSV(os.Stdout)
+	default:
+		log.Fatalf("unknown format %q, try table or csv", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func day(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		log.Fatalf("bad date %q, dates look like 2020-05-01", s)
+	}
+	return t
+}
@@ -0,0 +1,54 @@
+// A coffee shop you can order from over HTTP, see shop.Server for the endpoints
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/order"
+	"headfirstdesigntraining/decorator/orderlog"
+	"headfirstdesigntraining/decorator/shop"
+)
+
+var (
+	addr        = flag.String("addr", ":8080", "address to listen on")
+	catalogFile = flag.String("catalog", "", "JSON menu to use instead of the built-in drinks and condiments")
+	pricesFile  = flag.String("prices", "", "JSON price table to use instead of the default menu prices")
+	receiptsDir = flag.String("receipts", "", "directory to keep receipts in (kept in memory if empty)")
+	logFile     = flag.String("log", "orders.log", "order log for sales reports (no log if empty)")
+)
+
+func main() {
+	flag.Parse()
+	if *pricesFile != "" {
+		if err := beverage.LoadPrices(*pricesFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	menu := beverage.DefaultCatalog()
+	if *catalogFile != "" {
+		var err error
+		if menu, err = beverage.LoadCatalog(*catalogFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	store := shop.NewMemoryStore()
+	if *receiptsDir != "" {
+		var err error
+		if store, err = shop.NewFileStore(*receiptsDir); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	s := shop.NewServer(menu, store)
+	s.Taxes = []order.Tax{{Name: "GST", Rate: .05}, {Name: "PST", Rate: .07}}
+	s.Promotions = []order.Promotion{order.HappyHour(14, 16, 10)}
+	if *logFile != "" {
+		s.Log = orderlog.Open(*logFile)
+	}
+
+	log.Printf("Taking orders on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
@@ -0,0 +1,232 @@
+package inventory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// Stock is an amount of each stock item, e.g. {"espresso beans": 14, "cups": 1}
+type Stock map[string]float64
+
+// Usage is the stock every drink and condiment uses up, per size, keyed by name like a
+// beverage.PriceTable. Condiments use theirs once per serving.
+type Usage map[string]map[beverage.Size]Stock
+
+func DefaultUsage() Usage {
+	beans := func(bean string, tall, grande, venti float64) map[beverage.Size]Stock {
+		return map[beverage.Size]Stock{
+			beverage.Tall:   {bean: tall, "cups": 1},
+			beverage.Grande: {bean: grande, "cups": 1},
+			beverage.Venti:  {bean: venti, "cups": 1},
+		}
+	}
+	sized := func(item string, tall, grande, venti float64) map[beverage.Size]Stock {
+		return map[beverage.Size]Stock{
+			beverage.Tall:   {item: tall},
+			beverage.Grande: {item: grande},
+			beverage.Venti:  {item: venti},
+		}
+	}
+	return Usage{
+		"Espresso":     beans("espresso beans", 14, 21, 28),
+		"House Blend":  beans("house blend beans", 18, 24, 30),
+		"Dark Roast":   beans("dark roast beans", 18, 24, 30),
+		"Decaf":        beans("decaf beans", 18, 24, 30),
+		"Mocha":        sized("mocha syrup", 1, 1.5, 2),
+		"Soy":          sized("soy milk", 60, 80, 100),
+		"Whip":         sized("whipped cream", 30, 40, 50),
+		"Steamed Milk": sized("milk", 120, 160, 200),
+	}
+}
+
+// Needs adds up all the stock that goes into some drinks. Anything the Usage doesn't know about
+// doesn't use any stock.
+func (u Usage) Needs(bs ...beverage.Beverage) Stock {
+	need := Stock{}
+	for _, b := range bs {
+		for _, item := range b.LineItems() {
+			for stock, amount := range u[item.Name][item.Size] {
+				need[stock] += amount * float64(item.Quantity)
+			}
+		}
+	}
+	return need
+}
+
+// Observer hears about stock running low
+type Observer interface {
+	LowStock(item string, available, threshold float64)
+}
+
+// OutOfStockError is what an order gets when there isn't enough of something to make it
+type OutOfStockError struct {
+	Item      string
+	Needed    float64
+	Available float64
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("out of %s: need %g, only %g available", e.Item, e.Needed, e.Available)
+}
+
+// Inventory keeps track of the stock on hand, and what's been set aside for orders that are on
+// their way but haven't been made yet.
+type Inventory struct {
+	mu         sync.Mutex
+	usage      Usage
+	onHand     Stock
+	reserved   Stock
+	thresholds Stock
+	// Items that have already been reported low, so observers only hear about it once per restock
+	low       map[string]bool
+	observers []Observer
+}
+
+func New(usage Usage) *Inventory {
+	return &Inventory{
+		usage:      usage,
+		onHand:     Stock{},
+		reserved:   Stock{},
+		thresholds: Stock{},
+		low:        map[string]bool{},
+	}
+}
+
+// RegisterSubscriber adds an observer to hear about low stock
+func (inv *Inventory) RegisterSubscriber(o Observer) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.observers = append(inv.observers, o)
+}
+
+// RemoveSubscriber stops an observer hearing about low stock
+func (inv *Inventory) RemoveSubscriber(toRemove Observer) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for i, o := range inv.observers {
+		if o == toRemove {
+			inv.observers = append(inv.observers[:i], inv.observers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Restock adds stock to what's on hand
+func (inv *Inventory) Restock(item string, amount float64) {
+	inv.mu.Lock()
+	inv.onHand[item] += amount
+	notify := inv.checkLow(item)
+	inv.mu.Unlock()
+	notify()
+}
+
+// SetLowStock sets how low an item can get before observers hear about it
+func (inv *Inventory) SetLowStock(item string, threshold float64) {
+	inv.mu.Lock()
+	inv.thresholds[item] = threshold
+	notify := inv.checkLow(item)
+	inv.mu.Unlock()
+	notify()
+}
+
+// Available is what's on hand and not set aside for an order
+func (inv *Inventory) Available(item string) float64 {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.onHand[item] - inv.reserved[item]
+}
+
+// Reservation is stock set aside for an order. It's either committed once the order is made,
+// or released if it isn't.
+type Reservation struct {
+	inv   *Inventory
+	stock Stock
+	done  bool
+}
+
+// Reserve sets aside the stock for some drinks. It's all or nothing: if there isn't enough of
+// every item for every drink, nothing is reserved and the error says what ran out.
+func (inv *Inventory) Reserve(bs ...beverage.Beverage) (*Reservation, error) {
+	need := inv.usage.Needs(bs...)
+	inv.mu.Lock()
+	// Check items in a fixed order so the same shortage always gets the same error
+	items := make([]string, 0, len(need))
+	for item := range need {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	for _, item := range items {
+		if avail := inv.onHand[item] - inv.reserved[item]; need[item] > avail {
+			inv.mu.Unlock()
+			return nil, &OutOfStockError{Item: item, Needed: need[item], Available: avail}
+		}
+	}
+	var notifies []func()
+	for _, item := range items {
+		inv.reserved[item] += need[item]
+		notifies = append(notifies, inv.checkLow(item))
+	}
+	inv.mu.Unlock()
+	for _, n := range notifies {
+		n()
+	}
+	return &Reservation{inv: inv, stock: need}, nil
+}
+
+// Commit uses up the reserved stock
+func (r *Reservation) Commit() error {
+	return r.finish(true)
+}
+
+// Release puts the reserved stock back
+func (r *Reservation) Release() error {
+	return r.finish(false)
+}
+
+func (r *Reservation) finish(used bool) error {
+	inv := r.inv
+	inv.mu.Lock()
+	if r.done {
+		inv.mu.Unlock()
+		return fmt.Errorf("reservation has already been committed or released")
+	}
+	r.done = true
+	var notifies []func()
+	for item, amount := range r.stock {
+		inv.reserved[item] -= amount
+		if used {
+			inv.onHand[item] -= amount
+		}
+		notifies = append(notifies, inv.checkLow(item))
+	}
+	inv.mu.Unlock()
+	for _, n := range notifies {
+		n()
+	}
+	return nil
+}
+
+// checkLow works out whether an item has just run low (or been restocked). It has to be called
+// with the lock held, and hands back the notification to send once the lock is let go, so an
+// observer can look at the inventory without deadlocking.
+func (inv *Inventory) checkLow(item string) func() {
+	threshold, ok := inv.thresholds[item]
+	avail := inv.onHand[item] - inv.reserved[item]
+	if !ok || avail > threshold {
+		inv.low[item] = false
+		return func() {}
+	}
+	if inv.low[item] {
+		return func() {}
+	}
+	inv.low[item] = true
+	observers := append([]Observer(nil), inv.observers...)
+	return func() {
+		for _, o := range observers {
+			o.LowStock(item, avail, threshold)
+		}
+	}
+}
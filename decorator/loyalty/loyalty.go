@@ -0,0 +1,257 @@
+package loyalty
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/order"
+)
+
+type RewardKind int
+
+const (
+	// FreeCondiment takes the priciest condiment on a drink off the bill
+	FreeCondiment RewardKind = iota
+	// SizeUpgrade charges a drink at the size below the one that was ordered
+	SizeUpgrade
+	// FreeDrink takes the whole drink off the bill
+	FreeDrink
+)
+
+// Reward is something points can be spent on
+type Reward struct {
+	Name   string
+	Kind   RewardKind
+	Points int
+}
+
+// DefaultRewards are the reward tiers, cheapest first
+func DefaultRewards() []Reward {
+	return []Reward{
+		{Name: "Free condiment", Kind: FreeCondiment, Points: 50},
+		{Name: "Free size upgrade", Kind: SizeUpgrade, Points: 100},
+		{Name: "Free drink", Kind: FreeDrink, Points: 150},
+	}
+}
+
+// Program is a loyalty program: customers earn points for what they spend, and spend them on
+// rewards
+type Program struct {
+	mu              sync.Mutex
+	store           Store
+	pointsPerDollar int
+	rewards         []Reward
+	// held is the points each customer has on redemptions that haven't been committed yet
+	held map[string]int
+	// pending is every redemption that hasn't been committed or released yet
+	pending []*Redemption
+}
+
+func NewProgram(store Store, pointsPerDollar int, rewards []Reward) *Program {
+	return &Program{store: store, pointsPerDollar: pointsPerDollar, rewards: rewards, held: map[string]int{}}
+}
+
+// Rewards lists what points can be spent on
+func (p *Program) Rewards() []Reward {
+	return append([]Reward(nil), p.rewards...)
+}
+
+func (p *Program) Balance(customer string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.store.Balance(customer)
+}
+
+// Earn gives a customer points for some drinks they bought, returning their new balance.
+// Points are earned on every whole dollar of the drinks' Cost.
+func (p *Program) Earn(customer string, drinks ...beverage.Beverage) (int, error) {
+	var spent float64
+	for _, b := range drinks {
+		spent += b.Cost()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	balance, err := p.store.Balance(customer)
+	if err != nil {
+		return 0, err
+	}
+	balance += int(math.Floor(spent)) * p.pointsPerDollar
+	return balance, p.store.SetBalance(customer, balance)
+}
+
+// Redeem puts a reward on a drink in a cart, and shows up on the cart's Total as an adjustment
+// to whichever line the drink is on. The points are held until the order's placed and the
+// Redemption committed, or given back if it's released; if the drink comes out of the cart, the
+// reward comes off with it. A drink only gets one reward at a time.
+func (p *Program) Redeem(customer, reward string, c *order.Cart, line int) (*Redemption, error) {
+	var r *Reward
+	for i := range p.rewards {
+		if p.rewards[i].Name == reward {
+			r = &p.rewards[i]
+		}
+	}
+	if r == nil {
+		return nil, fmt.Errorf("no such reward %q", reward)
+	}
+	lines := c.Lines()
+	if line < 0 || line >= len(lines) {
+		return nil, fmt.Errorf("no line %d in the cart", line)
+	}
+	if _, err := r.discount(lines[line]); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pr := range p.pending {
+		if pr.cart == c && pr.line() == line {
+			return nil, fmt.Errorf("line %d already has a reward on it", line)
+		}
+	}
+	balance, err := p.store.Balance(customer)
+	if err != nil {
+		return nil, err
+	}
+	if available := balance - p.held[customer]; available < r.Points {
+		return nil, fmt.Errorf("%s needs %d points, %s only has %d", r.Name, r.Points, customer, available)
+	}
+	p.held[customer] += r.Points
+	red := &Redemption{program: p, customer: customer, reward: *r, cart: c, drink: lines[line]}
+	p.pending = append(p.pending, red)
+	c.AddPromotion(redemption{red})
+	return red, nil
+}
+
+// Redemption is a reward on a drink in a cart, waiting on the order to be placed
+type Redemption struct {
+	program  *Program
+	customer string
+	reward   Reward
+	cart     *order.Cart
+	drink    beverage.Beverage
+	// Only one of these ever happens
+	committed, released bool
+}
+
+// Commit spends the points, once the order's been placed with the given Total. A reward that
+// took nothing off that Total - its drink came out of the cart, or other promotions had
+// already made it free - is released rather than committed.
+func (r *Redemption) Commit(placed order.Total) error {
+	p := r.program
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r.committed || r.released {
+		return fmt.Errorf("%s has already been committed or released", r.reward.Name)
+	}
+	p.settle(r)
+	line, applied := r.line(), false
+	for _, a := range placed.Adjustments {
+		if a.Promotion == (redemption{}).Name() && a.Line == line && line >= 0 && a.Amount > 0 {
+			applied = true
+		}
+	}
+	if !applied {
+		r.released = true
+		return fmt.Errorf("%s didn't take anything off the order, so no points were spent", r.reward.Name)
+	}
+	r.committed = true
+	balance, err := p.store.Balance(r.customer)
+	if err != nil {
+		return err
+	}
+	return p.store.SetBalance(r.customer, balance-r.reward.Points)
+}
+
+// Release gives the points back and takes the reward off the cart, if the order isn't placed
+func (r *Redemption) Release() {
+	p := r.program
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !r.committed && !r.released {
+		r.released = true
+		p.settle(r)
+	}
+}
+
+// settle stops holding a redemption's points. p.mu has to be held.
+func (p *Program) settle(r *Redemption) {
+	p.held[r.customer] -= r.reward.Points
+	for i, pr := range p.pending {
+		if pr == r {
+			p.pending = append(p.pending[:i], p.pending[i+1:]...)
+			break
+		}
+	}
+}
+
+// line finds the drink the reward is on, or -1 if it's been taken out of the cart
+func (r *Redemption) line() int {
+	for i, b := range r.cart.Lines() {
+		// Drinks are told apart by the drink at the bottom of the chain - a cart line with the
+		// reward's drink on it is the reward's line
+		if beverage.Base(b) == beverage.Base(r.drink) && beverage.FormatOrder(b) == beverage.FormatOrder(r.drink) {
+			return i
+		}
+	}
+	return -1
+}
+
+// discount works out how much a reward takes off a drink
+func (r Reward) discount(b beverage.Beverage) (float64, error) {
+	switch r.Kind {
+	case FreeCondiment:
+		var best float64
+		for _, item := range beverage.Condiments(b) {
+			best = math.Max(best, item.UnitPrice)
+		}
+		if best == 0 {
+			return 0, fmt.Errorf("%s: there's no condiment on the drink", r.Name)
+		}
+		return best, nil
+	case SizeUpgrade:
+		size := b.GetSize()
+		if size == beverage.Tall {
+			return 0, fmt.Errorf("%s: a Tall is already the smallest size", r.Name)
+		}
+		// Price a copy a size down, so the drink in the cart is left alone
+		smaller, err := beverage.Resize(b, size-1)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		return b.Cost() - smaller.Cost(), nil
+	case FreeDrink:
+		return b.Cost(), nil
+	}
+	return 0, fmt.Errorf("%s: unknown kind of reward", r.Name)
+}
+
+// redemption is the promotion a Redemption puts on its cart
+type redemption struct {
+	*Redemption
+}
+
+func (r redemption) Name() string {
+	return "Loyalty reward"
+}
+
+func (r redemption) Apply(c *order.Cart, at time.Time) []order.Adjustment {
+	r.program.mu.Lock()
+	released := r.released
+	r.program.mu.Unlock()
+	if released {
+		return nil
+	}
+	line := r.line()
+	if line < 0 {
+		return nil
+	}
+	amount, err := r.reward.discount(c.Lines()[line])
+	if err != nil {
+		return nil
+	}
+	reason := fmt.Sprintf("%s for %d points", r.reward.Name, r.reward.Points)
+	return []order.Adjustment{{Line: line, Amount: amount, Reason: reason}}
+}
@@ -0,0 +1,88 @@
+package loyalty
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// Store keeps customers' points balances
+type Store interface {
+	Balance(customer string) (int, error)
+	SetBalance(customer string, points int) error
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{balances: map[string]int{}}
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	balances map[string]int
+}
+
+func (m *memoryStore) Balance(customer string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.balances[customer], nil
+}
+
+func (m *memoryStore) SetBalance(customer string, points int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances[customer] = points
+	return nil
+}
+
+// NewFileStore keeps balances in a JSON file of customer to points. The file doesn't have to
+// exist yet.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *fileStore) Balance(customer string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balances, err := f.load()
+	return balances[customer], err
+}
+
+func (f *fileStore) SetBalance(customer string, points int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balances, err := f.load()
+	if err != nil {
+		return err
+	}
+	balances[customer] = points
+	data, err := json.MarshalIndent(balances, "", "\t")
+	if err != nil {
+		return err
+	}
+	// Write the new balances alongside and swap them in, so a crash can't leave half a file
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *fileStore) load() (map[string]int, error) {
+	balances := map[string]int{}
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return balances, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &balances); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
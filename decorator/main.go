@@ -2,12 +2,34 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
 
 	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/inventory"
+	"headfirstdesigntraining/decorator/loyalty"
+	"headfirstdesigntraining/decorator/order"
+	"headfirstdesigntraining/decorator/receipt"
+	"headfirstdesigntraining/decorator/recipes"
+)
+
+var (
+	pricesFile  = flag.String("prices", "", "JSON price table to use instead of the default menu prices")
+	catalogFile = flag.String("catalog", "", "JSON menu to use instead of the built-in drinks and condiments")
 )
 
 func main() {
+	flag.Parse()
+	if *pricesFile != "" {
+		if err := beverage.LoadPrices(*pricesFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	e := beverage.Espresso()
 	fmt.Printf("%s: $%.2f\n", e.Description(), e.Cost())
 
@@ -19,11 +41,182 @@ func main() {
 	e = beverage.Whip(e)
 	fmt.Printf("%s: $%.2f\n", e.Description(), e.Cost())
 
-	// It's a big one (size-related pricing only implemented for soy)
+	// It's a big one (everything is priced by size now)
 	e.SetSize(beverage.Venti)
 	fmt.Printf("%s: $%.2f\n", e.Description(), e.Cost())
 
-	// Add some whip
+	// Add some more whip - it's the same layer of whip, just twice as much of it
 	e = beverage.Whip(e)
 	fmt.Printf("%s: $%.2f\n", e.Description(), e.Cost())
+
+	// Size can be set on any layer, before or after decorating, and every layer agrees on it
+	d := beverage.Whip(beverage.DarkRoast())
+	d.SetSize(beverage.Grande)
+	d = beverage.Mocha(d)
+	fmt.Printf("%s %s: $%.2f\n", d.GetSize(), d.Description(), d.Cost())
+
+	// Seasonal items can come off a menu file rather than out of the code
+	menu := beverage.DefaultCatalog()
+	if *catalogFile != "" {
+		var err error
+		if menu, err = beverage.LoadCatalog(*catalogFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Println("Today's menu:")
+	for _, name := range menu.Drinks() {
+		b, _ := menu.Drink(name)
+		fmt.Printf("  %s: $%.2f\n", name, b.Cost())
+	}
+	fmt.Println("Add-ons:", strings.Join(menu.Condiments(), ", "))
+
+	// Third pump of mocha onwards is half price
+	m := beverage.Times(beverage.Mocha, 3)(beverage.Espresso())
+	fmt.Printf("%s: $%.2f\n", m.Description(), m.Cost())
+
+	// Orders can also come in as text from the till
+	drink, err := menu.ParseOrder("venti dark roast, double mocha, soy, whip")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: $%.2f\n", beverage.FormatOrder(drink), drink.Cost())
+	for _, item := range drink.LineItems() {
+		fmt.Printf("  %-12s %-6s %d x $%.2f = $%.2f\n", item.Name, item.Size, item.Quantity, item.UnitPrice, item.Total())
+	}
+
+	// "Actually, no whip" - and swap the soy for steamed milk while we're at it
+	if beverage.Has(drink, "whip") {
+		drink = beverage.Remove(drink, "whip")
+	}
+	drink = beverage.Replace(drink, "soy", beverage.SteamedMilk)
+	fmt.Printf("%s: $%.2f\n", beverage.FormatOrder(drink), drink.Cost())
+
+	if _, err := menu.ParseOrder("tall dark roast, carmel"); err != nil {
+		fmt.Println("Couldn't ring that one up:", err)
+	}
+
+	// How bad is a venti mocha with whip, really?
+	v := beverage.Whip(beverage.Mocha(beverage.HouseBlend()))
+	v.SetSize(beverage.Venti)
+	n := v.Nutrition()
+	fmt.Printf("%s %s: %.0f calories, %.0fg sugar, %.0fmg caffeine, contains %v\n",
+		v.GetSize(), v.Description(), n.Calories, n.Sugar, n.Caffeine, v.Allergens())
+
+	// Some things just shouldn't be made
+	if _, _, err := beverage.Build(beverage.DefaultRules(), beverage.Espresso(), beverage.SteamedMilk, beverage.Soy); err != nil {
+		fmt.Println(err)
+	}
+	if _, warnings, err := beverage.Build(beverage.DefaultRules(), beverage.Decaf(), beverage.Times(beverage.Whip, 10)); err == nil {
+		fmt.Println("Made it, but:", warnings)
+	}
+
+	// Keep an eye on the mocha syrup
+	stock := inventory.New(inventory.DefaultUsage())
+	stock.RegisterSubscriber(lowStockAlert{})
+	for item, amount := range map[string]float64{"cups": 50, "dark roast beans": 500, "mocha syrup": 5, "whipped cream": 200} {
+		stock.Restock(item, amount)
+	}
+	stock.SetLowStock("mocha syrup", 2)
+	mochas, _ := beverage.ParseOrder("grande dark roast, double mocha, whip")
+	if r, err := stock.Reserve(mochas); err == nil {
+		r.Commit()
+	}
+	if _, err := stock.Reserve(mochas); err != nil {
+		fmt.Println("Can't make that:", err)
+	}
+
+	// Drinks can be saved and sent around as JSON
+	data, err := beverage.Marshal(drink)
+	if err != nil {
+		log.Fatal(err)
+	}
+	again, err := beverage.Unmarshal(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s -> %s\n", data, beverage.FormatOrder(again))
+
+	// Ring up a whole order, with tax and whatever promotions are running
+	cart := order.NewCart(
+		[]order.Tax{{Name: "GST", Rate: .05}, {Name: "PST", Rate: .07}},
+		order.BuyOneGetOne("Dark Roast"),
+		order.HappyHour(14, 16, 10),
+		order.FreeCondiment("Espresso", "Whip"),
+		order.Coupon("DUCKS", 5),
+	)
+	for _, o := range []string{"venti dark roast, mocha", "tall dark roast", "grande espresso, whip, soy"} {
+		b, err := menu.ParseOrder(o)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cart.Add(b)
+	}
+	if err := cart.ApplyCoupon("DUCKS"); err != nil {
+		log.Fatal(err)
+	}
+	happyHour := time.Date(2020, 5, 1, 15, 0, 0, 0, time.Local)
+	fmt.Print(cart.Total(happyHour))
+	if err := receipt.Text(os.Stdout, receipt.New(happyHour, cart.Total(happyHour)), 32); err != nil {
+		log.Fatal(err)
+	}
+
+	// Regulars earn points, and spend them on the next order
+	rewards := loyalty.NewProgram(loyalty.NewMemoryStore(), 10, loyalty.DefaultRewards())
+	for i := 0; i < 20; i++ {
+		rewards.Earn("Mallard", cart.Lines()...)
+	}
+	points, _ := rewards.Balance("Mallard")
+	fmt.Println("Mallard has", points, "points")
+	next := order.NewCart(nil)
+	next.Add(beverage.Mocha(beverage.DarkRoast()))
+	redemption, err := rewards.Redeem("Mallard", "Free drink", next, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	placed := next.Total(time.Now())
+	fmt.Print(placed)
+	// The points only come off once the order's placed
+	if err := redemption.Commit(placed); err != nil {
+		log.Fatal(err)
+	}
+
+	// "The usual, please"
+	book := recipes.NewBook(recipes.NewMemoryStore(), menu)
+	if err := book.Save("Mallard", "my usual", beverage.Whip(beverage.Times(beverage.Mocha, 2)(beverage.DarkRoast()))); err != nil {
+		log.Fatal(err)
+	}
+	usual, err := book.Reorder("Mallard", "my usual")
+	if err != nil {
+		log.Fatal(err)
+	}
+	code := recipes.Code(usual)
+	shared, err := book.Decode(code)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Mallard's usual is %s (%s), shared as %s: %s $%.2f\n", usual.Description(), beverage.FormatOrder(usual), code, shared.Description(), shared.Cost())
+
+	// Prices go up, but the books still need last year's
+	history, err := beverage.NewPriceHistory(
+		beverage.PriceList{EffectiveFrom: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Prices: beverage.DefaultPrices()},
+		beverage.PriceList{EffectiveFrom: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Prices: beverage.PriceTable{
+			"Mocha": {beverage.Tall: .25, beverage.Grande: .30, beverage.Venti: .35},
+		}},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, year := range []int{2020, 2021} {
+		cost, err := history.CostAt(usual, time.Date(year, 6, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("In %d the usual cost $%.2f\n", year, cost)
+	}
+}
+
+type lowStockAlert struct{}
+
+func (lowStockAlert) LowStock(item string, available, threshold float64) {
+	fmt.Printf("Running low on %s: %g left\n", item, available)
 }
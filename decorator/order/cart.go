@@ -0,0 +1,191 @@
+package order
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// Tax is a sales tax charged on the discounted order, e.g. Tax{Name: "GST", Rate: .05}
+type Tax struct {
+	Name string
+	Rate float64
+}
+
+// Promotion takes money off a Cart. It reports what it took off as Adjustments, so a Total can
+// show exactly which promotion touched which line.
+type Promotion interface {
+	Name() string
+	Apply(c *Cart, at time.Time) []Adjustment
+}
+
+// Adjustment is money a promotion took off the order
+type Adjustment struct {
+	Promotion string
+	// Line is the index of the cart line the adjustment applies to, or -1 for the whole order
+	Line   int
+	Amount float64
+	// Percent, if it's set, takes the Amount as a percentage of what's left of the line, or of
+	// the order, once the promotions before it have come off
+	Percent float64
+	Reason  string
+}
+
+// Cart holds the drinks in an order, along with what it's taxed at and the promotions it's
+// eligible for.
+type Cart struct {
+	lines      []beverage.Beverage
+	taxes      []Tax
+	promotions []Promotion
+	coupons    []string
+}
+
+func NewCart(taxes []Tax, promotions ...Promotion) *Cart {
+	return &Cart{taxes: taxes, promotions: promotions}
+}
+
+// AddPromotion makes the cart eligible for another promotion, e.g. a loyalty reward being
+// redeemed. It applies after the promotions the cart was made with.
+func (c *Cart) AddPromotion(p Promotion) {
+	c.promotions = append(c.promotions, p)
+}
+
+// Add puts a drink in the cart, returning its line number
+func (c *Cart) Add(b beverage.Beverage) int {
+	c.lines = append(c.lines, b)
+	return len(c.lines) - 1
+}
+
+// Remove takes a line out of the cart. Later lines move up to fill the gap.
+func (c *Cart) Remove(line int) error {
+	if line < 0 || line >= len(c.lines) {
+		return fmt.Errorf("no line %d in the cart", line)
+	}
+	c.lines = append(c.lines[:line], c.lines[line+1:]...)
+	return nil
+}
+
+// Lines lists the drinks in the cart
+func (c *Cart) Lines() []beverage.Beverage {
+	return append([]beverage.Beverage(nil), c.lines...)
+}
+
+// ApplyCoupon adds a coupon code to the order, as long as one of the cart's promotions takes it
+func (c *Cart) ApplyCoupon(code string) error {
+	for _, p := range c.promotions {
+		if cp, ok := p.(interface{ Code() string }); ok && strings.EqualFold(cp.Code(), code) {
+			c.coupons = append(c.coupons, code)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown coupon %q", code)
+}
+
+// HasCoupon checks whether a coupon code has been applied to the order
+func (c *Cart) HasCoupon(code string) bool {
+	for _, cc := range c.coupons {
+		if strings.EqualFold(cc, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// LineTotal is a drink in a Total, with what it cost before and after promotions
+type LineTotal struct {
+	Beverage beverage.Beverage
+	Price    float64
+	Discount float64
+}
+
+// TaxCharged is how much of one Tax a Total was charged
+type TaxCharged struct {
+	Tax
+	Amount float64
+}
+
+// Total is the priced-up order, with every promotion that went into it
+type Total struct {
+	Lines       []LineTotal
+	Adjustments []Adjustment
+	Subtotal    float64
+	Discount    float64
+	Taxes       []TaxCharged
+	Tax         float64
+	Total       float64
+}
+
+// Total prices the cart as of a given time (happy hour only happens at certain times, after all).
+// Promotions are applied in the order the cart was given them, and none of them can take a line,
+// or the order, below zero.
+func (c *Cart) Total(at time.Time) Total {
+	var t Total
+	remaining := make([]float64, len(c.lines))
+	for i, b := range c.lines {
+		price := round(b.Cost())
+		t.Lines = append(t.Lines, LineTotal{Beverage: b, Price: price})
+		t.Subtotal += price
+		remaining[i] = price
+	}
+	orderRemaining := t.Subtotal
+	for _, p := range c.promotions {
+		for _, a := range p.Apply(c, at) {
+			a.Promotion = p.Name()
+			if a.Percent > 0 {
+				left := orderRemaining
+				if a.Line >= 0 {
+					left = remaining[a.Line]
+				}
+				a.Amount = left * a.Percent / 100
+			}
+			a.Amount = math.Min(round(a.Amount), orderRemaining)
+			if a.Line >= 0 {
+				a.Amount = math.Min(a.Amount, remaining[a.Line])
+				remaining[a.Line] -= a.Amount
+				t.Lines[a.Line].Discount += a.Amount
+			}
+			if a.Amount <= 0 {
+				continue
+			}
+			orderRemaining -= a.Amount
+			t.Discount += a.Amount
+			t.Adjustments = append(t.Adjustments, a)
+		}
+	}
+	taxable := t.Subtotal - t.Discount
+	for _, tax := range c.taxes {
+		amount := round(taxable * tax.Rate)
+		t.Taxes = append(t.Taxes, TaxCharged{Tax: tax, Amount: amount})
+		t.Tax += amount
+	}
+	t.Total = round(taxable + t.Tax)
+	return t
+}
+
+// String lays the total out as an audit trail: every line, every promotion and every tax
+func (t Total) String() string {
+	var b strings.Builder
+	for i, l := range t.Lines {
+		fmt.Fprintf(&b, "%d. %s %s: $%.2f\n", i+1, l.Beverage.GetSize(), l.Beverage.Description(), l.Price)
+	}
+	fmt.Fprintf(&b, "Subtotal: $%.2f\n", t.Subtotal)
+	for _, a := range t.Adjustments {
+		on := "order"
+		if a.Line >= 0 {
+			on = fmt.Sprintf("line %d", a.Line+1)
+		}
+		fmt.Fprintf(&b, "  %s (%s, %s): -$%.2f\n", a.Promotion, on, a.Reason, a.Amount)
+	}
+	for _, tax := range t.Taxes {
+		fmt.Fprintf(&b, "%s (%.4g%%): $%.2f\n", tax.Name, tax.Rate*100, tax.Amount)
+	}
+	fmt.Fprintf(&b, "Total: $%.2f\n", t.Total)
+	return b.String()
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
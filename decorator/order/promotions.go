@@ -0,0 +1,139 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// BuyOneGetOne makes every second drink of a kind free. The cheaper drink of each pair is the
+// free one.
+func BuyOneGetOne(drink string) Promotion {
+	return buyOneGetOne{drink: drink}
+}
+
+type buyOneGetOne struct {
+	drink string
+}
+
+func (p buyOneGetOne) Name() string {
+	return fmt.Sprintf("Buy one %s, get one free", p.drink)
+}
+
+func (p buyOneGetOne) Apply(c *Cart, at time.Time) []Adjustment {
+	var eligible []int
+	lines := c.Lines()
+	for i, b := range lines {
+		if isDrink(b, p.drink) {
+			eligible = append(eligible, i)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return lines[eligible[i]].Cost() > lines[eligible[j]].Cost()
+	})
+	var adjs []Adjustment
+	for i := 1; i < len(eligible); i += 2 {
+		line := eligible[i]
+		adjs = append(adjs, Adjustment{Line: line, Amount: lines[line].Cost(), Reason: "free drink"})
+	}
+	return adjs
+}
+
+// HappyHour takes a percentage off every drink ordered between two hours of the day, e.g.
+// HappyHour(14, 16, 20) is 20% off from 2pm until 4pm. A window can run past midnight:
+// HappyHour(22, 2, 20) is 20% off from 10pm until 2am.
+func HappyHour(from, until int, percent float64) Promotion {
+	return happyHour{from: from, until: until, percent: percent}
+}
+
+type happyHour struct {
+	from, until int
+	percent     float64
+}
+
+func (p happyHour) Name() string {
+	return fmt.Sprintf("Happy hour %g%% off", p.percent)
+}
+
+func (p happyHour) Apply(c *Cart, at time.Time) []Adjustment {
+	if !p.on(at.Hour()) {
+		return nil
+	}
+	var adjs []Adjustment
+	for i, b := range c.Lines() {
+		reason := fmt.Sprintf("%g%% off between %d:00 and %d:00", p.percent, p.from, p.until)
+		adjs = append(adjs, Adjustment{Line: i, Amount: b.Cost() * p.percent / 100, Reason: reason})
+	}
+	return adjs
+}
+
+// on is whether it's happy hour at an hour of the day
+func (p happyHour) on(h int) bool {
+	if p.from > p.until {
+		return h >= p.from || h < p.until
+	}
+	return h >= p.from && h < p.until
+}
+
+// FreeCondiment gives one of a condiment away free with a drink, e.g. a free pump of mocha with
+// every espresso.
+func FreeCondiment(drink, condiment string) Promotion {
+	return freeCondiment{drink: drink, condiment: condiment}
+}
+
+type freeCondiment struct {
+	drink, condiment string
+}
+
+func (p freeCondiment) Name() string {
+	return fmt.Sprintf("Free %s with %s", p.condiment, p.drink)
+}
+
+func (p freeCondiment) Apply(c *Cart, at time.Time) []Adjustment {
+	var adjs []Adjustment
+	for i, b := range c.Lines() {
+		if !isDrink(b, p.drink) {
+			continue
+		}
+		for _, item := range beverage.Condiments(b) {
+			if strings.EqualFold(item.Name, p.condiment) {
+				adjs = append(adjs, Adjustment{Line: i, Amount: item.UnitPrice, Reason: "free " + item.Name})
+				break
+			}
+		}
+	}
+	return adjs
+}
+
+// Coupon takes a percentage off the whole order when its code has been applied to the cart. It
+// comes off whatever's left once the promotions before it have been taken off.
+func Coupon(code string, percent float64) Promotion {
+	return coupon{code: code, percent: percent}
+}
+
+type coupon struct {
+	code    string
+	percent float64
+}
+
+func (p coupon) Name() string {
+	return fmt.Sprintf("Coupon %s", p.code)
+}
+
+func (p coupon) Code() string {
+	return p.code
+}
+
+func (p coupon) Apply(c *Cart, at time.Time) []Adjustment {
+	if !c.HasCoupon(p.code) {
+		return nil
+	}
+	return []Adjustment{{Line: -1, Percent: p.percent, Reason: fmt.Sprintf("%g%% off", p.percent)}}
+}
+
+func isDrink(b beverage.Beverage, name string) bool {
+	return strings.EqualFold(beverage.Base(b).Description(), name)
+}
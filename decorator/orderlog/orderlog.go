@@ -0,0 +1,74 @@
+package orderlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"headfirstdesigntraining/decorator/receipt"
+)
+
+// Log is a record of every completed order, kept as a file of JSON receipts, one per line
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// Open uses the log at path, which is created on the first Append if it doesn't exist yet
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append records a completed order
+func (l *Log) Append(r receipt.Receipt) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read finds the orders placed from one time up until (not including) another. A zero time
+// leaves that end open.
+func (l *Log) Read(from, until time.Time) ([]receipt.Receipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rs []receipt.Receipt
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<20)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var r receipt.Receipt
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", l.path, line, err)
+		}
+		if (!from.IsZero() && r.PlacedAt.Before(from)) || (!until.IsZero() && !r.PlacedAt.Before(until)) {
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs, s.Err()
+}
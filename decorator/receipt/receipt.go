@@ -0,0 +1,133 @@
+package receipt
+
+import (
+	"math"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/order"
+)
+
+type LineItem struct {
+	Name      string        `json:"name"`
+	Size      beverage.Size `json:"size"`
+	UnitPrice float64       `json:"unit_price"`
+	Quantity  int           `json:"quantity"`
+	Discount  float64       `json:"discount,omitempty"`
+	Total     float64       `json:"total"`
+}
+
+// Drink is a drink on a receipt, with everything that went into its price
+type Drink struct {
+	Order       string        `json:"order"`
+	Description string        `json:"description"`
+	Size        beverage.Size `json:"size"`
+	LineItems   []LineItem    `json:"line_items"`
+	Price       float64       `json:"price"`
+	// Discount is what promotions took off the drink
+	Discount float64  `json:"discount,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type Adjustment struct {
+	Promotion string `json:"promotion"`
+	// Drink is the index of the drink the adjustment applies to, or -1 for the whole order
+	Drink  int     `json:"drink"`
+	Reason string  `json:"reason"`
+	Amount float64 `json:"amount"`
+}
+
+type Tax struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
+// Receipt is an order, priced up and ready to print
+type Receipt struct {
+	ID          string       `json:"id"`
+	PlacedAt    time.Time    `json:"placed_at"`
+	Drinks      []Drink      `json:"drinks"`
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+	Subtotal    float64      `json:"subtotal"`
+	Discount    float64      `json:"discount"`
+	Taxes       []Tax        `json:"taxes,omitempty"`
+	Tax         float64      `json:"tax"`
+	Total       float64      `json:"total"`
+}
+
+// New makes the receipt for an order that was totalled up at a given time
+func New(at time.Time, t order.Total) Receipt {
+	r := Receipt{
+		PlacedAt: at,
+		Subtotal: round(t.Subtotal),
+		Discount: round(t.Discount),
+		Tax:      round(t.Tax),
+		Total:    t.Total,
+	}
+	for _, l := range t.Lines {
+		d := NewDrink(l.Beverage)
+		d.Discount = round(l.Discount)
+		r.Drinks = append(r.Drinks, d)
+	}
+	for _, a := range t.Adjustments {
+		r.Adjustments = append(r.Adjustments, Adjustment{Promotion: a.Promotion, Drink: a.Line, Reason: a.Reason, Amount: a.Amount})
+	}
+	for _, tax := range t.Taxes {
+		r.Taxes = append(r.Taxes, Tax{Name: tax.Name, Rate: tax.Rate, Amount: tax.Amount})
+	}
+	return r
+}
+
+// DrinkAdjustments lists the promotions that went to one drink
+func (r Receipt) DrinkAdjustments(drink int) []Adjustment {
+	var adjs []Adjustment
+	for _, a := range r.Adjustments {
+		if a.Drink == drink {
+			adjs = append(adjs, a)
+		}
+	}
+	return adjs
+}
+
+// OrderAdjustments lists the promotions that went to the order as a whole
+func (r Receipt) OrderAdjustments() []Adjustment {
+	return r.DrinkAdjustments(-1)
+}
+
+// DrinkDiscount adds up what promotions took off individual drinks
+func (r Receipt) DrinkDiscount() float64 {
+	var d float64
+	for _, dr := range r.Drinks {
+		d += dr.Discount
+	}
+	return round(d)
+}
+
+// NewDrink breaks a drink down for a receipt
+func NewDrink(b beverage.Beverage) Drink {
+	d := Drink{
+		Order:       beverage.FormatOrder(b),
+		Description: b.Description(),
+		Size:        b.GetSize(),
+		Price:       round(b.Cost()),
+	}
+	// Receipts list the drink first, then its condiments in the order they were added
+	items := b.LineItems()
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		d.LineItems = append(d.LineItems, LineItem{
+			Name:      item.Name,
+			Size:      item.Size,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			Discount:  round(item.Discount),
+			Total:     round(item.Total()),
+		})
+	}
+	return d
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,108 @@
+package receipt
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/order"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with what the renderers print now")
+
+// golden is a receipt with a bit of everything on it: a condiment ordered more than once, a
+// quantity discount, a drink promotion, an order promotion, two taxes and a warning. It's
+// priced the way the shop prices an order, so the goldens follow the real prices.
+var golden = func() Receipt {
+	at := time.Date(2020, 5, 1, 15, 4, 0, 0, time.UTC)
+	cart := order.NewCart(
+		[]order.Tax{{Name: "GST", Rate: .05}, {Name: "PST", Rate: .07}},
+		order.HappyHour(14, 16, 10),
+		order.Coupon("DUCKS", 5),
+	)
+	venti := beverage.Whip(beverage.Times(beverage.Mocha, 3)(beverage.DarkRoast()))
+	venti.SetSize(beverage.Venti)
+	cart.Add(venti)
+	cart.Add(beverage.Times(beverage.Whip, 3)(beverage.Espresso()))
+	if err := cart.ApplyCoupon("DUCKS"); err != nil {
+		panic(err)
+	}
+	r := New(at, cart.Total(at))
+	r.ID = "42"
+	for i, b := range cart.Lines() {
+		// Warnings are the shop's to check, just as it does when an order's placed
+		warnings, err := beverage.Validate(b, beverage.DefaultRules()...)
+		if err != nil {
+			panic(err)
+		}
+		for _, w := range warnings {
+			r.Drinks[i].Warnings = append(r.Drinks[i].Warnings, w.Message)
+		}
+	}
+	return r
+}()
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		golden string
+		render func(io.Writer, Receipt) error
+	}{
+		{"text32.golden", func(w io.Writer, r Receipt) error { return Text(w, r, 32) }},
+		{"text42.golden", func(w io.Writer, r Receipt) error { return Text(w, r, 42) }},
+		{"html.golden", HTML},
+		{"json.golden", JSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := tt.render(&got, golden); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := ioutil.WriteFile(path, got.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to write it)", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("doesn't match %s (run with -update if that's on purpose):\ngot:\n%s\nwant:\n%s", path, got.Bytes(), want)
+			}
+		})
+	}
+}
+
+func TestTextTooNarrow(t *testing.T) {
+	for _, width := range []int{-1, 0, 5, MinTextWidth - 1} {
+		if err := Text(ioutil.Discard, golden, width); err == nil {
+			t.Errorf("Text at %d wide should fail", width)
+		}
+	}
+	if err := Text(ioutil.Discard, golden, MinTextWidth); err != nil {
+		t.Errorf("Text at %d wide: %v", MinTextWidth, err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("  ! ", "3 Whip is too many for a Tall, the most is 2", 20)
+	want := []string{"  ! 3 Whip is too", "    many for a Tall,", "    the most is 2"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wrap() = %q, want %q", got, want)
+	}
+}
+
+func TestRowDoesntOverflow(t *testing.T) {
+	// Prices wider than the line still print, rather than panicking
+	if got := row("TOTAL", "$123456789.00", 5); got != " $123456789.00" {
+		t.Errorf("row() = %q", got)
+	}
+}
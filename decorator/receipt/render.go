@@ -0,0 +1,159 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+)
+
+// MinTextWidth is the narrowest receipt Text prints; anything narrower can't fit a price
+const MinTextWidth = 20
+
+// Text prints a receipt for a thermal printer that fits width characters on a line (32 and 42
+// are the usual paper widths)
+func Text(w io.Writer, r Receipt, width int) error {
+	if width < MinTextWidth {
+		return fmt.Errorf("receipts have to be at least %d characters wide, not %d", MinTextWidth, width)
+	}
+	t := texttemplate.Must(texttemplate.New("receipt").Funcs(texttemplate.FuncMap{
+		"money":   money,
+		"percent": percent,
+		"row":     func(left, right string) string { return row(left, right, width) },
+		"center":  func(s string) string { return center(s, width) },
+		"rule":    func() string { return strings.Repeat("-", width) },
+		"note":    func(s string) []string { return wrap("  ! ", s, width) },
+	}).Parse(textTemplate))
+	return t.Execute(w, r)
+}
+
+const textTemplate = `{{center "HEAD FIRST COFFEE"}}
+{{if .ID}}{{center (printf "Order #%s" .ID)}}
+{{end}}{{center (.PlacedAt.Format "2006-01-02 15:04")}}
+{{rule}}
+{{range $i, $d := .Drinks}}{{range $j, $item := $d.LineItems}}{{if eq $j 0}}{{row (printf "%s %s" $item.Size $item.Name) (money $item.Total)}}
+{{else if gt $item.Quantity 1}}{{row (printf "  %s x%d" $item.Name $item.Quantity) (money $item.Total)}}
+{{else}}{{row (printf "  %s" $item.Name) (money $item.Total)}}
+{{end}}{{end}}{{range $.DrinkAdjustments $i}}{{row (printf "  %s" .Promotion) (printf "-%s" (money .Amount))}}
+{{end}}{{range $d.Warnings}}{{range note .}}{{.}}
+{{end}}{{end}}{{end}}{{rule}}
+{{row "Subtotal" (money .Subtotal)}}
+{{if .DrinkDiscount}}{{row "Drink discounts" (printf "-%s" (money .DrinkDiscount))}}
+{{end}}{{range .OrderAdjustments}}{{row .Promotion (printf "-%s" (money .Amount))}}
+{{end}}{{range .Taxes}}{{row (printf "%s %.4g%%" .Name (percent .Rate)) (money .Amount)}}
+{{end}}{{row "TOTAL" (money .Total)}}
+{{rule}}
+{{center "Thanks for stopping by!"}}
+`
+
+// HTML prints a receipt as a web page (for emailing, or showing on the kiosk)
+func HTML(w io.Writer, r Receipt) error {
+	t := htmltemplate.Must(htmltemplate.New("receipt").Funcs(htmltemplate.FuncMap{
+		"money":   money,
+		"percent": percent,
+	}).Parse(htmlTemplate))
+	return t.Execute(w, r)
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt{{if .ID}} #{{.ID}}{{end}}</title>
+</head>
+<body>
+<h1>Head First Coffee</h1>
+<p>{{if .ID}}Order #{{.ID}}, {{end}}{{.PlacedAt.Format "2006-01-02 15:04"}}</p>
+<table>
+<thead><tr><th>Item</th><th>Size</th><th>Qty</th><th>Each</th><th>Amount</th></tr></thead>
+<tbody>
+{{- range $i, $d := .Drinks}}
+{{- range $j, $item := $d.LineItems}}
+<tr{{if ne $j 0}} class="condiment"{{end}}><td>{{$item.Name}}</td><td>{{$item.Size}}</td><td>{{$item.Quantity}}</td><td>{{money $item.UnitPrice}}</td><td>{{money $item.Total}}</td></tr>
+{{- end}}
+{{- range $.DrinkAdjustments $i}}
+<tr class="discount"><td colspan="4">{{.Promotion}} ({{.Reason}})</td><td>-{{money .Amount}}</td></tr>
+{{- end}}
+{{- range $d.Warnings}}
+<tr class="warning"><td colspan="5">{{.}}</td></tr>
+{{- end}}
+{{- end}}
+</tbody>
+<tfoot>
+<tr><td colspan="4">Subtotal</td><td>{{money .Subtotal}}</td></tr>
+{{- if .DrinkDiscount}}
+<tr class="discount"><td colspan="4">Drink discounts</td><td>-{{money .DrinkDiscount}}</td></tr>
+{{- end}}
+{{- range .OrderAdjustments}}
+<tr class="discount"><td colspan="4">{{.Promotion}} ({{.Reason}})</td><td>-{{money .Amount}}</td></tr>
+{{- end}}
+{{- range .Taxes}}
+<tr><td colspan="4">{{.Name}} ({{printf "%.4g" (percent .Rate)}}%)</td><td>{{money .Amount}}</td></tr>
+{{- end}}
+<tr class="total"><td colspan="4">Total</td><td>{{money .Total}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`
+
+// JSON prints a receipt as JSON, the same shape the ordering API hands back
+func JSON(w io.Writer, r Receipt) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func money(amount float64) string {
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+func percent(rate float64) float64 {
+	return rate * 100
+}
+
+// row puts left and right at either end of a line, cutting left short if they don't both fit
+func row(left, right string, width int) string {
+	space := width - utf8.RuneCountInString(right) - 1
+	if space < 0 {
+		space = 0
+	}
+	if n := utf8.RuneCountInString(left); n > space {
+		left = string([]rune(left)[:space])
+	}
+	pad := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if pad < 1 {
+		pad = 1
+	}
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// wrap breaks text into lines no wider than width, the first starting with prefix and the rest
+// lined up under it. A word too long for a line gets a line of its own.
+func wrap(prefix, text string, width int) []string {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	var lines []string
+	line := prefix
+	for _, word := range strings.Fields(text) {
+		if line != prefix && line != indent && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = indent
+		}
+		if line != prefix && line != indent {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+func center(s string, width int) string {
+	pad := (width - utf8.RuneCountInString(s)) / 2
+	if pad < 0 {
+		pad = 0
+	}
+	return strings.Repeat(" ", pad) + s
+}
@@ -0,0 +1,101 @@
+package recipes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// Recipe is a drink a customer saved to order again
+type Recipe struct {
+	Name  string
+	Drink beverage.Document
+}
+
+// Book is where customers keep their usual drinks
+type Book struct {
+	store Store
+	menu  *beverage.Catalog
+}
+
+// NewBook keeps recipes in a store, making them off a menu when they're reordered
+func NewBook(store Store, menu *beverage.Catalog) *Book {
+	return &Book{store: store, menu: menu}
+}
+
+// Save keeps a drink under a name, replacing whatever the customer had saved under it before
+func (b *Book) Save(customer, name string, drink beverage.Beverage) error {
+	if name == "" {
+		return fmt.Errorf("recipes need a name")
+	}
+	rs, err := b.store.Recipes(customer)
+	if err != nil {
+		return err
+	}
+	rs[name] = beverage.NewDocument(drink)
+	return b.store.SetRecipes(customer, rs)
+}
+
+// List finds all of a customer's recipes, by name
+func (b *Book) List(customer string) ([]Recipe, error) {
+	rs, err := b.store.Recipes(customer)
+	if err != nil {
+		return nil, err
+	}
+	var list []Recipe
+	for name, d := range rs {
+		list = append(list, Recipe{Name: name, Drink: d})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Reorder makes a saved drink again. It's made fresh off the menu, so it's charged today's prices.
+func (b *Book) Reorder(customer, name string) (beverage.Beverage, error) {
+	rs, err := b.store.Recipes(customer)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := rs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s has no recipe called %q", customer, name)
+	}
+	return b.menu.Beverage(d)
+}
+
+// codeVersion starts every share code, so the format can change without old codes breaking
+const codeVersion = "2"
+
+// maxCodeLength is the longest share code Decode reads, so a junk code isn't decoded at
+// length. It's only a sanity check on the text; what the drink can be is bounded by the menu,
+// which won't make one with more than beverage.MaxCondiments condiments or more than
+// beverage.MaxQuantity of any of them.
+const maxCodeLength = 1024
+
+// Code turns a drink into a short code that can be passed around and turned back into the same
+// drink with Decode. It's the drink's Document, as compact JSON in URL-safe base64.
+func Code(drink beverage.Beverage) string {
+	// A Document is plain strings and numbers, so it always marshals
+	data, _ := beverage.Marshal(drink)
+	return codeVersion + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode makes the drink a share code stands for, off the book's menu
+func (b *Book) Decode(code string) (beverage.Beverage, error) {
+	if len(code) > maxCodeLength || !strings.HasPrefix(code, codeVersion) {
+		return nil, fmt.Errorf("that isn't a recipe code")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(code[len(codeVersion):])
+	if err != nil {
+		return nil, fmt.Errorf("that isn't a recipe code")
+	}
+	// The menu checks the drink is on it, and isn't too big to price
+	drink, err := b.menu.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("recipe code: %w", err)
+	}
+	return drink, nil
+}
@@ -0,0 +1,98 @@
+package recipes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+// Store keeps every customer's saved drinks, by the name they saved them under
+type Store interface {
+	Recipes(customer string) (map[string]beverage.Document, error)
+	SetRecipes(customer string, recipes map[string]beverage.Document) error
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{recipes: map[string]map[string]beverage.Document{}}
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	recipes map[string]map[string]beverage.Document
+}
+
+func (m *memoryStore) Recipes(customer string) (map[string]beverage.Document, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return copyRecipes(m.recipes[customer]), nil
+}
+
+func (m *memoryStore) SetRecipes(customer string, recipes map[string]beverage.Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recipes[customer] = copyRecipes(recipes)
+	return nil
+}
+
+func copyRecipes(recipes map[string]beverage.Document) map[string]beverage.Document {
+	c := map[string]beverage.Document{}
+	for name, d := range recipes {
+		c[name] = d
+	}
+	return c
+}
+
+// NewFileStore keeps recipes in a JSON file of customer to recipes. The file doesn't have to
+// exist yet.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *fileStore) Recipes(customer string) (map[string]beverage.Document, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all, err := f.load()
+	return copyRecipes(all[customer]), err
+}
+
+func (f *fileStore) SetRecipes(customer string, recipes map[string]beverage.Document) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all, err := f.load()
+	if err != nil {
+		return err
+	}
+	all[customer] = recipes
+	data, err := json.MarshalIndent(all, "", "\t")
+	if err != nil {
+		return err
+	}
+	// Write the new recipes alongside and swap them in, so a crash can't leave half a file
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *fileStore) load() (map[string]map[string]beverage.Document, error) {
+	all := map[string]map[string]beverage.Document{}
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return all, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/receipt"
+)
+
+// DrinkSales is how one base drink sold. Revenue is after the promotions that went to the drink,
+// before tax.
+type DrinkSales struct {
+	Name    string
+	Count   int
+	Revenue float64
+}
+
+// AttachRate is how often a condiment was added to a drink
+type AttachRate struct {
+	Condiment string
+	Drinks    int
+	Rate      float64
+}
+
+type SizeMix struct {
+	Size  beverage.Size
+	Count int
+	Share float64
+}
+
+type HourSales struct {
+	Hour    int
+	Orders  int
+	Revenue float64
+}
+
+// Report sums up a stretch of orders
+type Report struct {
+	Orders  int
+	Drinks  int
+	Revenue float64 // After every promotion, before tax
+	ByDrink []DrinkSales
+	Attach  []AttachRate
+	Sizes   []SizeMix
+	ByHour  []HourSales
+}
+
+// Build works out a Report from a pile of receipts (in local time, for the hour-of-day sales)
+func Build(rs []receipt.Receipt) Report {
+	var r Report
+	drinks := map[string]*DrinkSales{}
+	attach := map[string]int{}
+	sizes := map[beverage.Size]int{}
+	hours := map[int]*HourSales{}
+
+	for _, rec := range rs {
+		r.Orders++
+		revenue := rec.Subtotal - rec.Discount
+		r.Revenue += revenue
+		h := rec.PlacedAt.Local().Hour()
+		if hours[h] == nil {
+			hours[h] = &HourSales{Hour: h}
+		}
+		hours[h].Orders++
+		hours[h].Revenue += revenue
+
+		for _, d := range rec.Drinks {
+			if len(d.LineItems) == 0 {
+				continue
+			}
+			r.Drinks++
+			sizes[d.Size]++
+			// The drink itself always comes first on a receipt, then its condiments
+			base := d.LineItems[0].Name
+			if drinks[base] == nil {
+				drinks[base] = &DrinkSales{Name: base}
+			}
+			drinks[base].Count++
+			drinks[base].Revenue += d.Price - d.Discount
+			seen := map[string]bool{}
+			for _, item := range d.LineItems[1:] {
+				if !seen[item.Name] {
+					seen[item.Name] = true
+					attach[item.Name]++
+				}
+			}
+		}
+	}
+
+	for _, d := range drinks {
+		d.Revenue = round(d.Revenue)
+		r.ByDrink = append(r.ByDrink, *d)
+	}
+	sort.Slice(r.ByDrink, func(i, j int) bool {
+		if r.ByDrink[i].Revenue != r.ByDrink[j].Revenue {
+			return r.ByDrink[i].Revenue > r.ByDrink[j].Revenue
+		}
+		return r.ByDrink[i].Name < r.ByDrink[j].Name
+	})
+	for c, n := range attach {
+		r.Attach = append(r.Attach, AttachRate{Condiment: c, Drinks: n, Rate: float64(n) / float64(r.Drinks)})
+	}
+	sort.Slice(r.Attach, func(i, j int) bool {
+		if r.Attach[i].Drinks != r.Attach[j].Drinks {
+			return r.Attach[i].Drinks > r.Attach[j].Drinks
+		}
+		return r.Attach[i].Condiment < r.Attach[j].Condiment
+	})
+	for _, s := range beverage.Sizes {
+		if sizes[s] > 0 {
+			r.Sizes = append(r.Sizes, SizeMix{Size: s, Count: sizes[s], Share: float64(sizes[s]) / float64(r.Drinks)})
+		}
+	}
+	for h := 0; h < 24; h++ {
+		if hs, ok := hours[h]; ok {
+			hs.Revenue = round(hs.Revenue)
+			r.ByHour = append(r.ByHour, *hs)
+		}
+	}
+	r.Revenue = round(r.Revenue)
+	return r
+}
+
+// rows lays the report out as one table: section, what, count, amount and share
+func (r Report) rows() [][]string {
+	money := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	pct := func(f float64) string { return strconv.FormatFloat(f*100, 'f', 1, 64) + "%" }
+	rows := [][]string{
+		{"section", "item", "count", "amount", "share"},
+		{"total", "orders", strconv.Itoa(r.Orders), money(r.Revenue), ""},
+		{"total", "drinks", strconv.Itoa(r.Drinks), "", ""},
+	}
+	for _, d := range r.ByDrink {
+		rows = append(rows, []string{"drink", d.Name, strconv.Itoa(d.Count), money(d.Revenue), ""})
+	}
+	for _, a := range r.Attach {
+		rows = append(rows, []string{"attach", a.Condiment, strconv.Itoa(a.Drinks), "", pct(a.Rate)})
+	}
+	for _, s := range r.Sizes {
+		rows = append(rows, []string{"size", s.Size.String(), strconv.Itoa(s.Count), "", pct(s.Share)})
+	}
+	for _, h := range r.ByHour {
+		rows = append(rows, []string{"hour", fmt.Sprintf("%02d:00", h.Hour), strconv.Itoa(h.Orders), money(h.Revenue), ""})
+	}
+	return rows
+}
+
+// WriteCSV writes the report out as CSV, for the spreadsheet people
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.WriteAll(r.rows())
+	return cw.Error()
+}
+
+// WriteTable writes the report out as a table, for people
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range r.rows() {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/receipt"
+)
+
+// ItemImpact is how a price change moves what one base drink or condiment brings in
+type ItemImpact struct {
+	Name          string
+	Count         int
+	Before, After float64
+}
+
+// Impact is what a pile of orders would have come to under one price list, and under another.
+// Amounts are before promotions and tax.
+type Impact struct {
+	Orders, Drinks int
+	// Changed counts the drinks the new prices make cost something different
+	Changed       int
+	Before, After float64
+	ByItem        []ItemImpact
+}
+
+// Reprice works out the Impact of going from one price table to another on some orders. Each
+// drink is priced again from its receipt line items, quantity pricing included.
+func Reprice(rs []receipt.Receipt, old, new beverage.PriceTable) Impact {
+	var im Impact
+	items := map[string]*ItemImpact{}
+	for _, rec := range rs {
+		im.Orders++
+		for _, d := range rec.Drinks {
+			im.Drinks++
+			var before, after float64
+			for _, item := range d.LineItems {
+				b, a := linePrice(item, old), linePrice(item, new)
+				before += b
+				after += a
+				if items[item.Name] == nil {
+					items[item.Name] = &ItemImpact{Name: item.Name}
+				}
+				items[item.Name].Count += item.Quantity
+				items[item.Name].Before += b
+				items[item.Name].After += a
+			}
+			if round(before) != round(after) {
+				im.Changed++
+			}
+			im.Before += before
+			im.After += after
+		}
+	}
+	for _, item := range items {
+		item.Before, item.After = round(item.Before), round(item.After)
+		im.ByItem = append(im.ByItem, *item)
+	}
+	sort.Slice(im.ByItem, func(i, j int) bool { return im.ByItem[i].Name < im.ByItem[j].Name })
+	im.Before, im.After = round(im.Before), round(im.After)
+	return im
+}
+
+// linePrice prices a receipt line item from a table. Quantity discounts take the same share off
+// as they did on the receipt.
+func linePrice(item receipt.LineItem, t beverage.PriceTable) float64 {
+	full := item.UnitPrice * float64(item.Quantity)
+	share := 1.0
+	if full > 0 {
+		share = 1 - item.Discount/full
+	}
+	return t.Price(item.Name, item.Size) * float64(item.Quantity) * share
+}
+
+// WriteTable writes the impact out as a table, for people
+func (im Impact) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "item\tcount\tbefore\tafter\tchange\n")
+	for _, item := range im.ByItem {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.2f\t%s\n", item.Name, item.Count, item.Before, item.After, change(item.Before, item.After))
+	}
+	fmt.Fprintf(tw, "total (%d orders, %d of %d drinks changed)\t\t%.2f\t%.2f\t%s\n",
+		im.Orders, im.Changed, im.Drinks, im.Before, im.After, change(im.Before, im.After))
+	return tw.Flush()
+}
+
+func change(before, after float64) string {
+	diff := round(after - before)
+	if before == 0 {
+		return fmt.Sprintf("%+.2f", diff)
+	}
+	return fmt.Sprintf("%+.2f (%+.1f%%)", diff, diff/before*100)
+}
@@ -0,0 +1,364 @@
+package shop
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+	"headfirstdesigntraining/decorator/inventory"
+	"headfirstdesigntraining/decorator/order"
+	"headfirstdesigntraining/decorator/orderlog"
+	"headfirstdesigntraining/decorator/receipt"
+)
+
+// Server is a JSON API for ordering off a menu:
+//
+//	GET  /menu                 what's on the menu, and what it all costs in each size
+//	POST /price                price a drink without ordering it
+//	POST /orders               place an order, getting its receipt back
+//	GET  /orders/{id}/receipt  fetch the receipt for an order (/orders/{id} works too), as JSON
+//	                           or ?format=text or html
+type Server struct {
+	Menu       *beverage.Catalog
+	Store      Store
+	Rules      []beverage.Rule
+	Taxes      []order.Tax
+	Promotions []order.Promotion
+	// Inventory, if there is one, has stock reserved and used up for every order placed
+	Inventory *inventory.Inventory
+	// Log, if there is one, gets a copy of the receipt for every order placed
+	Log *orderlog.Log
+	Now func() time.Time
+
+	mux *http.ServeMux
+}
+
+// NewServer makes a Server for a menu, holding drinks to the house rules. Taxes, promotions and
+// an inventory can be set on it before it starts serving.
+func NewServer(menu *beverage.Catalog, store Store) *Server {
+	s := &Server{
+		Menu:  menu,
+		Store: store,
+		Rules: beverage.DefaultRules(),
+		Now:   time.Now,
+		mux:   http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/menu", s.handleMenu)
+	s.mux.HandleFunc("/price", s.handlePrice)
+	s.mux.HandleFunc("/orders", s.handleOrders)
+	s.mux.HandleFunc("/orders/", s.handleReceipt)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// CondimentRequest asks for a condiment, once unless a Quantity is given
+type CondimentRequest struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+// DrinkRequest asks for a drink, either as order text ("venti dark roast, double mocha") or
+// piece by piece
+type DrinkRequest struct {
+	Order      string             `json:"order,omitempty"`
+	Drink      string             `json:"drink,omitempty"`
+	Size       beverage.Size      `json:"size"`
+	Condiments []CondimentRequest `json:"condiments,omitempty"`
+}
+
+type OrderRequest struct {
+	Drinks  []DrinkRequest `json:"drinks"`
+	Coupons []string       `json:"coupons,omitempty"`
+}
+
+type MenuItem struct {
+	Name   string              `json:"name"`
+	Prices beverage.SizePrices `json:"prices"`
+}
+
+type Menu struct {
+	Drinks     []MenuItem `json:"drinks"`
+	Condiments []MenuItem `json:"condiments"`
+}
+
+// Problem is one thing wrong with a drink in a request
+type Problem struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Error is the body of every error response
+type Error struct {
+	Message string `json:"message"`
+	// Drink is the index of the drink in the request that the error is about, if it's about one
+	Drink *int `json:"drink,omitempty"`
+	// Word and Offset point at the part of an order's text that couldn't be understood
+	Word       string    `json:"word,omitempty"`
+	Offset     *int      `json:"offset,omitempty"`
+	Violations []Problem `json:"violations,omitempty"`
+}
+
+func (s *Server) handleMenu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	var m Menu
+	for _, name := range s.Menu.Drinks() {
+		prices := beverage.SizePrices{}
+		for _, size := range beverage.Sizes {
+			b, _ := s.Menu.Drink(name)
+			b.SetSize(size)
+			prices[size] = b.Cost()
+		}
+		m.Drinks = append(m.Drinks, MenuItem{Name: name, Prices: prices})
+	}
+	for _, name := range s.Menu.Condiments() {
+		add, _ := s.Menu.Condiment(name)
+		prices := beverage.SizePrices{}
+		for _, size := range beverage.Sizes {
+			// A condiment is priced by what it adds to a drink, so put it on any old drink
+			b := add(beverage.Espresso())
+			b.SetSize(size)
+			prices[size] = b.LineItems()[0].UnitPrice
+		}
+		m.Condiments = append(m.Condiments, MenuItem{Name: name, Prices: prices})
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// maxBody is as big as a request can get. An order for a whole office fits in a lot less.
+const maxBody = 64 << 10
+
+// maxDrinks is the most drinks one order can have
+const maxDrinks = 20
+
+func (s *Server) handlePrice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req DrinkRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, Error{Message: "bad request body: " + err.Error()})
+		return
+	}
+	b, warnings, e := s.build(req)
+	if e != nil {
+		writeError(w, http.StatusUnprocessableEntity, *e)
+		return
+	}
+	writeJSON(w, http.StatusOK, priced(b, warnings))
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var req OrderRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, Error{Message: "bad request body: " + err.Error()})
+		return
+	}
+	if len(req.Drinks) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, Error{Message: "no drinks ordered"})
+		return
+	}
+	if len(req.Drinks) > maxDrinks {
+		writeError(w, http.StatusUnprocessableEntity, Error{Message: fmt.Sprintf("can't order more than %d drinks at once", maxDrinks)})
+		return
+	}
+
+	cart := order.NewCart(s.Taxes, s.Promotions...)
+	var bs []beverage.Beverage
+	var warnings [][]beverage.Violation
+	for i, dr := range req.Drinks {
+		b, ws, e := s.build(dr)
+		if e != nil {
+			e.Drink = &i
+			writeError(w, http.StatusUnprocessableEntity, *e)
+			return
+		}
+		cart.Add(b)
+		bs = append(bs, b)
+		warnings = append(warnings, ws)
+	}
+	for _, code := range req.Coupons {
+		if err := cart.ApplyCoupon(code); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, Error{Message: err.Error()})
+			return
+		}
+	}
+
+	var res *inventory.Reservation
+	if s.Inventory != nil {
+		var err error
+		if res, err = s.Inventory.Reserve(bs...); err != nil {
+			writeError(w, http.StatusConflict, Error{Message: err.Error()})
+			return
+		}
+	}
+
+	now := s.Now()
+	rec := receipt.New(now, cart.Total(now))
+	for i, ws := range warnings {
+		rec.Drinks[i].Warnings = messages(ws)
+	}
+	if err := s.Store.Save(&rec); err != nil {
+		if res != nil {
+			res.Release()
+		}
+		writeError(w, http.StatusInternalServerError, Error{Message: err.Error()})
+		return
+	}
+	if res != nil {
+		res.Commit()
+	}
+	if s.Log != nil {
+		if err := s.Log.Append(rec); err != nil {
+			log.Printf("Couldn't log order %s: %v", rec.ID, err)
+		}
+	}
+	w.Header().Set("Location", "/orders/"+rec.ID+"/receipt")
+	writeJSON(w, http.StatusCreated, rec)
+}
+
+func (s *Server) handleReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/orders/"), "/receipt")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, Error{Message: "not found"})
+		return
+	}
+	rec, err := s.Store.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		writeError(w, http.StatusNotFound, Error{Message: fmt.Sprintf("no order %s", id)})
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, Error{Message: err.Error()})
+		return
+	}
+	// Receipts are printed in full before any of it is sent, so a failure can still be an error
+	var buf bytes.Buffer
+	var contentType string
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		writeJSON(w, http.StatusOK, rec)
+		return
+	case "text":
+		contentType = "text/plain; charset=utf-8"
+		err = receipt.Text(&buf, *rec, 42)
+	case "html":
+		contentType = "text/html; charset=utf-8"
+		err = receipt.HTML(&buf, *rec)
+	default:
+		writeError(w, http.StatusBadRequest, Error{Message: fmt.Sprintf("unknown receipt format %q, try json, text or html", format)})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, Error{Message: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	buf.WriteTo(w)
+}
+
+// build makes the drink a request asks for, checked against the house rules
+func (s *Server) build(req DrinkRequest) (beverage.Beverage, []beverage.Violation, *Error) {
+	var b beverage.Beverage
+	if req.Order != "" {
+		var err error
+		if b, err = s.Menu.ParseOrder(req.Order); err != nil {
+			e := Error{Message: err.Error()}
+			var perr *beverage.ParseError
+			if errors.As(err, &perr) {
+				e.Word, e.Offset = perr.Word, &perr.Offset
+			}
+			return nil, nil, &e
+		}
+	} else {
+		if len(req.Condiments) > beverage.MaxCondiments {
+			return nil, nil, &Error{Message: fmt.Sprintf("can't have more than %d condiments", beverage.MaxCondiments)}
+		}
+		base, err := s.Menu.Drink(req.Drink)
+		if err != nil {
+			return nil, nil, &Error{Message: err.Error()}
+		}
+		base.SetSize(req.Size)
+		b = base
+		for _, c := range req.Condiments {
+			add, err := s.Menu.Condiment(c.Name)
+			if err != nil {
+				return nil, nil, &Error{Message: err.Error()}
+			}
+			n := c.Quantity
+			if n == 0 {
+				n = 1
+			} else if n < 0 || n > beverage.MaxQuantity {
+				return nil, nil, &Error{Message: fmt.Sprintf("can't have %d %s", n, c.Name)}
+			}
+			b = beverage.Times(add, n)(b)
+		}
+	}
+
+	warnings, err := beverage.Validate(b, s.Rules...)
+	var verr *beverage.ValidationError
+	if errors.As(err, &verr) {
+		return nil, nil, &Error{Message: err.Error(), Violations: problems(verr.Violations)}
+	}
+	return b, warnings, nil
+}
+
+func priced(b beverage.Beverage, warnings []beverage.Violation) receipt.Drink {
+	d := receipt.NewDrink(b)
+	d.Warnings = messages(warnings)
+	return d
+}
+
+func messages(vs []beverage.Violation) []string {
+	var msgs []string
+	for _, v := range vs {
+		msgs = append(msgs, v.Message)
+	}
+	return msgs
+}
+
+func problems(vs []beverage.Violation) []Problem {
+	ps := make([]Problem, len(vs))
+	for i, v := range vs {
+		ps[i] = Problem{Severity: v.Severity.String(), Message: v.Message}
+	}
+	return ps
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, http.StatusMethodNotAllowed, Error{Message: "method not allowed"})
+}
+
+func writeError(w http.ResponseWriter, status int, e Error) {
+	writeJSON(w, status, struct {
+		Error Error `json:"error"`
+	}{e})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
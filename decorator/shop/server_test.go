@@ -0,0 +1,41 @@
+package shop
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"headfirstdesigntraining/decorator/beverage"
+)
+
+func TestLongOrdersAreTurnedAwayQuickly(t *testing.T) {
+	long := "tall decaf" + strings.Repeat(", mocha, whip", 50)
+	var condiments []string
+	for i := 0; i < 100; i++ {
+		condiments = append(condiments, `{"name": "soy"}`)
+	}
+	drinks := strings.Repeat(`{"order": "tall decaf"},`, 100)
+	tests := []struct {
+		name, path, body string
+	}{
+		{"order text", "/price", `{"order": "` + long + `"}`},
+		{"condiment list", "/price", `{"drink": "decaf", "condiments": [` + strings.Join(condiments, ",") + `]}`},
+		{"drink list", "/orders", `{"drinks": [` + strings.TrimSuffix(drinks, ",") + `]}`},
+	}
+	s := NewServer(beverage.DefaultCatalog(), NewMemoryStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			start := time.Now()
+			s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			if took := time.Since(start); took > 100*time.Millisecond {
+				t.Errorf("took %s", took)
+			}
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("got %d, want %d: %s", w.Code, http.StatusUnprocessableEntity, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package shop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"headfirstdesigntraining/decorator/receipt"
+)
+
+// ErrNotFound is returned by a Store that doesn't have the receipt it was asked for
+var ErrNotFound = errors.New("receipt not found")
+
+// Store keeps receipts for orders that have been placed
+type Store interface {
+	// Save stores a receipt, filling in its ID
+	Save(r *receipt.Receipt) error
+	Get(id string) (*receipt.Receipt, error)
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{receipts: map[string]receipt.Receipt{}}
+}
+
+type memoryStore struct {
+	mu       sync.Mutex
+	receipts map[string]receipt.Receipt
+	next     int
+}
+
+func (m *memoryStore) Save(r *receipt.Receipt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next++
+	r.ID = fmt.Sprintf("%d", m.next)
+	m.receipts[r.ID] = *r
+	return nil
+}
+
+func (m *memoryStore) Get(id string) (*receipt.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.receipts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &r, nil
+}
+
+// NewFileStore keeps receipts as JSON files in a directory, one per order, so they survive a
+// restart
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+type fileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (f *fileStore) Save(r *receipt.Receipt) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Receipts are numbered on from however many are already in the directory
+	existing, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for n := len(existing) + 1; ; n++ {
+		r.ID = fmt.Sprintf("%d", n)
+		if _, err := os.Stat(f.path(r.ID)); os.IsNotExist(err) {
+			break
+		}
+	}
+	data, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(f.path(r.ID), data, 0644)
+}
+
+func (f *fileStore) Get(id string) (*receipt.Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if filepath.Base(id) != id {
+		return nil, ErrNotFound
+	}
+	data, err := ioutil.ReadFile(f.path(id))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var r receipt.Receipt
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (f *fileStore) path(id string) string {
+	return filepath.Join(f.dir, id+".json")
+}
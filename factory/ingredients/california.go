@@ -0,0 +1,24 @@
+package ingredients
+
+// CaliforniaProfile is what California makes its pizzas with
+func CaliforniaProfile() Profile {
+	return Profile{
+		Region:    "California",
+		Dough:     Ingredient{Name: "thin crust dough", Attributes: map[string]string{"crust": "thin"}},
+		Sauce:     Ingredient{Name: "basil pesto", Attributes: map[string]string{"style": "pesto"}},
+		Cheese:    Ingredient{Name: "goat cheese", Attributes: map[string]string{"kind": "goat"}},
+		Veggies:   []Ingredient{{Name: "arugula", Attributes: map[string]string{"added": "after baking"}}, {Name: "baby spinach"}},
+		Pepperoni: Ingredient{Name: "turkey pepperoni"},
+		Clam:      Ingredient{Name: "fresh clams", Attributes: map[string]string{"frozen": "no"}},
+		Recipes: map[string]Recipe{
+			"bbq chicken": {
+				Sauce:    Ingredient{Name: "smoky BBQ sauce", Attributes: map[string]string{"style": "BBQ"}},
+				Toppings: []Ingredient{{Name: "grilled chicken"}, {Name: "red onion"}},
+			},
+		},
+	}
+}
+
+func CaliforniaIngredientFactory() PizzaIngredientFactory {
+	return builtIn(CaliforniaProfile())
+}
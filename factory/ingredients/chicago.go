@@ -1,32 +1,18 @@
 package ingredients
 
-func ChicagoIngredientFactory() PizzaIngredientFactory {
-	return chicagoIngredientFactory{}
-}
-
-type chicagoIngredientFactory struct {}
-
-func (c chicagoIngredientFactory) CreateDough() Dough {
-	return thickCrustDough{name: "Thicc dough"}
-}
-
-func (c chicagoIngredientFactory) CreateSauce() Sauce {
-	return plumTomatoSauce{name: "plum tomato sauce"}
-}
-
-func (c chicagoIngredientFactory) CreateCheese() Cheese {
-	return mozzarellaCheese{name: "cheese"}
-}
-
-func (c chicagoIngredientFactory) CreateVeggies() []Veggies {
-	return []Veggies{eggplant{name: "eggplant"}, blackOlives{name: "black olives"}}
+// ChicagoProfile is what Chicago makes its pizzas with
+func ChicagoProfile() Profile {
+	return Profile{
+		Region:    "Chicago",
+		Dough:     Ingredient{Name: "Thicc dough", Attributes: map[string]string{"crust": "thick"}},
+		Sauce:     Ingredient{Name: "plum tomato sauce", Attributes: map[string]string{"style": "chunky"}},
+		Cheese:    Ingredient{Name: "cheese", Attributes: map[string]string{"kind": "mozzarella"}},
+		Veggies:   []Ingredient{{Name: "eggplant"}, {Name: "spinach"}, {Name: "black olives"}},
+		Pepperoni: Ingredient{Name: "sliced pepperoni"},
+		Clam:      Ingredient{Name: "unfortunately frozen clams", Attributes: map[string]string{"frozen": "yes"}},
+	}
 }
 
-func (c chicagoIngredientFactory) CreatePepperoni() Pepperoni {
-	return slicedPepperoni{name: "sliced pepperoni"}
-}
-
-func (c chicagoIngredientFactory) CreateClam() Clam {
-	return frozenClam{name: "unfortunately frozen clams"}
+func ChicagoIngredientFactory() PizzaIngredientFactory {
+	return builtIn(ChicagoProfile())
 }
-
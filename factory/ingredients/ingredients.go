@@ -1,65 +0,0 @@
-package ingredients
-
-type thickCrustDough struct{
-	name string
-}
-
-func (d thickCrustDough) Name() string {
-	return d.name
-}
-
-type plumTomatoSauce struct{
-	name string
-}
-
-func (d plumTomatoSauce) Name() string {
-	return d.name
-}
-
-type eggplant struct{
-	name string
-}
-
-func (d eggplant) Name() string {
-	return d.name
-}
-
-type slicedPepperoni struct{
-	name string
-}
-
-func (d slicedPepperoni) Name() string {
-	return d.name
-}
-
-type mozzarellaCheese struct{
-	name string
-}
-
-func (d mozzarellaCheese) Name() string {
-	return d.name
-}
-
-type frozenClam struct{
-	name string
-}
-
-func (d frozenClam) Name() string {
-	return d.name
-}
-
-type spinach struct{
-	name string
-}
-
-func (d spinach) Name() string {
-	return d.name
-}
-
-type blackOlives struct{
-	name string
-}
-
-func (d blackOlives) Name() string {
-	return d.name
-}
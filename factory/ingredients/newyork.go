@@ -1,32 +1,18 @@
 package ingredients
 
-func NYIngredientFactory() PizzaIngredientFactory {
-	return nyIngredientFactory{}
-}
-
-type nyIngredientFactory struct {}
-
-func (f nyIngredientFactory) CreateDough() Dough {
-	return thickCrustDough{name: "Thin dough"}
-}
-
-func (f nyIngredientFactory) CreateSauce() Sauce {
-	return plumTomatoSauce{name: "marinara"}
-}
-
-func (f nyIngredientFactory) CreateCheese() Cheese {
-	return mozzarellaCheese{name: "cheese"}
-}
-
-func (f nyIngredientFactory) CreateVeggies() []Veggies {
-	return []Veggies{}
+// NYProfile is what New York makes its pizzas with
+func NYProfile() Profile {
+	return Profile{
+		Region:    "New York",
+		Dough:     Ingredient{Name: "Thin dough", Attributes: map[string]string{"crust": "thin"}},
+		Sauce:     Ingredient{Name: "marinara", Attributes: map[string]string{"style": "smooth"}},
+		Cheese:    Ingredient{Name: "cheese", Attributes: map[string]string{"kind": "mozzarella"}},
+		Veggies:   []Ingredient{{Name: "baby spinach"}, {Name: "grilled eggplant"}},
+		Pepperoni: Ingredient{Name: "sliced pepperoni"},
+		Clam:      Ingredient{Name: "fresh clams", Attributes: map[string]string{"frozen": "no"}},
+	}
 }
 
-func (f nyIngredientFactory) CreatePepperoni() Pepperoni {
-	return slicedPepperoni{name: "sliced pepperoni"}
-}
-
-func (f nyIngredientFactory) CreateClam() Clam {
-	return frozenClam{name: "fresh clams"}
+func NYIngredientFactory() PizzaIngredientFactory {
+	return builtIn(NYProfile())
 }
-
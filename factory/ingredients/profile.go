@@ -0,0 +1,230 @@
+package ingredients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Ingredient is an ingredient as a profile lists it, with anything else worth knowing about it
+// (how thick the crust is, where the clams come from, ...)
+type Ingredient struct {
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Profile is everything a region makes its pizzas with, written down rather than coded up
+type Profile struct {
+	Region    string       `json:"region"`
+	Dough     Ingredient   `json:"dough"`
+	Sauce     Ingredient   `json:"sauce"`
+	Cheese    Ingredient   `json:"cheese"`
+	Veggies   []Ingredient `json:"veggies"`
+	Pepperoni Ingredient   `json:"pepperoni"`
+	Clam      Ingredient   `json:"clam"`
+	// Recipes are the pizzas the region makes with something of their own, by pizza type
+	Recipes map[string]Recipe `json:"recipes,omitempty"`
+}
+
+// Recipe is what one of a region's pizzas is made with besides the region's usual ingredients
+type Recipe struct {
+	// Sauce is used instead of the region's, if it's named
+	Sauce    Ingredient   `json:"sauce"`
+	Toppings []Ingredient `json:"toppings"`
+}
+
+// Validate checks the profile names every ingredient a pizza can ask for
+func (p Profile) Validate() error {
+	var missing []string
+	for _, i := range []struct {
+		what       string
+		ingredient Ingredient
+	}{{"dough", p.Dough}, {"sauce", p.Sauce}, {"cheese", p.Cheese}, {"pepperoni", p.Pepperoni}, {"clam", p.Clam}} {
+		if i.ingredient.Name == "" {
+			missing = append(missing, i.what)
+		}
+	}
+	if len(p.Veggies) == 0 {
+		missing = append(missing, "veggies")
+	}
+	for n, v := range p.Veggies {
+		if v.Name == "" {
+			missing = append(missing, fmt.Sprintf("veggie %d's name", n+1))
+		}
+	}
+	for name, r := range p.Recipes {
+		for n, t := range r.Toppings {
+			if t.Name == "" {
+				missing = append(missing, fmt.Sprintf("%s topping %d's name", name, n+1))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s profile has no %s", p.Region, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ReadProfile decodes a JSON regional profile such as
+//
+//	{
+//		"region": "Detroit",
+//		"dough": {"name": "focaccia dough", "attributes": {"crust": "thick"}},
+//		"sauce": {"name": "tomato sauce", "attributes": {"served": "on top"}},
+//		"cheese": {"name": "brick cheese"},
+//		"veggies": [{"name": "banana peppers"}],
+//		"pepperoni": {"name": "cup and char pepperoni"},
+//		"clam": {"name": "frozen clams"},
+//		"recipes": {"bbq chicken": {"sauce": {"name": "BBQ sauce"}, "toppings": [{"name": "grilled chicken"}]}}
+//	}
+//
+// and checks it's got every ingredient
+func ReadProfile(r io.Reader) (Profile, error) {
+	var p Profile
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p); err != nil {
+		return Profile{}, fmt.Errorf("reading ingredient profile: %w", err)
+	}
+	if p.Region == "" {
+		return Profile{}, fmt.Errorf("ingredient profile has no region")
+	}
+	return p, p.Validate()
+}
+
+// LoadProfile reads a JSON regional profile from a file, see ReadProfile
+func LoadProfile(path string) (Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Profile{}, err
+	}
+	defer f.Close()
+	p, err := ReadProfile(f)
+	if err != nil {
+		return Profile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// builtIn makes the ingredient factory for one of the profiles that comes with the package,
+// which are always complete
+func builtIn(p Profile) PizzaIngredientFactory {
+	f, err := ProfileIngredientFactory(p)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// ProfileIngredientFactory makes ingredients the way a profile says to
+func ProfileIngredientFactory(p Profile) (PizzaIngredientFactory, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return profileIngredientFactory{profile: p}, nil
+}
+
+type profileIngredientFactory struct {
+	profile Profile
+	// recipe is the pizza type the ingredients are for, "" for the region's usual ones
+	recipe string
+}
+
+// Toppings are what a recipe lays on besides the region's usual ingredients
+type Toppings interface {
+	Name() string
+}
+
+// RecipeIngredientFactory is an ingredient factory that makes some pizzas their own way. Every
+// factory out of a profile is one.
+type RecipeIngredientFactory interface {
+	PizzaIngredientFactory
+	// ForRecipe makes a pizza type's ingredients, with its own sauce if it has one
+	ForRecipe(pizzaType string) RecipeIngredientFactory
+	// CreateToppings makes the recipe's toppings, none for the region's usual ingredients
+	CreateToppings() []Toppings
+}
+
+func (f profileIngredientFactory) ForRecipe(pizzaType string) RecipeIngredientFactory {
+	return profileIngredientFactory{profile: f.profile, recipe: pizzaType}
+}
+
+func (f profileIngredientFactory) CreateDough() Dough {
+	return configured(f.profile.Dough)
+}
+
+func (f profileIngredientFactory) CreateSauce() Sauce {
+	if r := f.profile.Recipes[f.recipe]; r.Sauce.Name != "" {
+		return configured(r.Sauce)
+	}
+	return configured(f.profile.Sauce)
+}
+
+func (f profileIngredientFactory) CreateCheese() Cheese {
+	return configured(f.profile.Cheese)
+}
+
+func (f profileIngredientFactory) CreateVeggies() []Veggies {
+	var vs []Veggies
+	for _, v := range f.profile.Veggies {
+		vs = append(vs, configured(v))
+	}
+	return vs
+}
+
+func (f profileIngredientFactory) CreatePepperoni() Pepperoni {
+	return configured(f.profile.Pepperoni)
+}
+
+func (f profileIngredientFactory) CreateClam() Clam {
+	return configured(f.profile.Clam)
+}
+
+func (f profileIngredientFactory) CreateToppings() []Toppings {
+	var ts []Toppings
+	for _, t := range f.profile.Recipes[f.recipe].Toppings {
+		ts = append(ts, configured(t))
+	}
+	return ts
+}
+
+// Attributed is an ingredient that knows more about itself than its name. Every ingredient out
+// of a profile is one.
+type Attributed interface {
+	// Attribute looks up something the profile said about the ingredient, "" if it didn't
+	Attribute(key string) string
+	// Attributes lists everything the profile said about the ingredient
+	Attributes() map[string]string
+}
+
+// profileIngredient is any ingredient out of a profile
+type profileIngredient struct {
+	name       string
+	attributes map[string]string
+}
+
+func configured(i Ingredient) profileIngredient {
+	attrs := map[string]string{}
+	for k, v := range i.Attributes {
+		attrs[k] = v
+	}
+	return profileIngredient{name: i.Name, attributes: attrs}
+}
+
+func (i profileIngredient) Name() string {
+	return i.name
+}
+
+func (i profileIngredient) Attribute(key string) string {
+	return i.attributes[key]
+}
+
+func (i profileIngredient) Attributes() map[string]string {
+	attrs := map[string]string{}
+	for k, v := range i.attributes {
+		attrs[k] = v
+	}
+	return attrs
+}
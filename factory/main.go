@@ -2,23 +2,98 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"headfirstdesigntraining/factory/ingredients"
 	"headfirstdesigntraining/factory/pizzas"
 	"headfirstdesigntraining/factory/pizzastore"
 )
 
+var (
+	profileFile = flag.String("profile", "", "JSON ingredient profile for another region to open a store in, e.g. detroit.json")
+	menuFile    = flag.String("menu", "", "JSON menu of more pizzas to make, e.g. menu.json")
+)
+
 func main() {
+	flag.Parse()
 	piStore := pizzastore.ChicagoStylePizzaStore()
 
-	piStore.OrderPizza("cheese")
-	piStore.OrderPizza("pepperoni")
+	order(piStore, "cheese")
+	order(piStore, "pepperoni")
 
 	piStore = pizzastore.NewYorkStylePizzaStore()
 
-	piStore.OrderPizza("pepperoni")
-	piStore.OrderPizza("cheese")
+	order(piStore, "pepperoni")
+	order(piStore, "cheese")
+	order(piStore, "veggie")
+	order(piStore, "clam")
 
 	piStore = pizzastore.NewPizzaStore(pizzas.ChicagoStyleFactory())
 
-	piStore.OrderPizza("cheese")
-	piStore.OrderPizza("pepperoni")
+	order(piStore, "cheese")
+	order(piStore, "pepperoni")
+
+	// Not every pizza is on every menu
+	fmt.Println("On the menu:", strings.Join(piStore.Menu(), ", "))
+	order(piStore, "pineapple")
+
+	// New pizzas go on the menu without touching the regional factories
+	pizzas.Register("margherita", func(r pizzas.Region) pizzas.Pizza {
+		return pizzas.Cheese(r.Name+" Margherita", r)
+	}, "New York")
+	order(pizzastore.NewYorkStylePizzaStore(), "margherita")
+	order(piStore, "margherita")
+
+	// Or out of a file
+	if *menuFile != "" {
+		if err := pizzas.LoadMenu(*menuFile); err != nil {
+			log.Fatal(err)
+		}
+		for _, region := range pizzas.Regions() {
+			f, err := pizzas.RegionFactory(region)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("On the %s menu: %s\n", region, strings.Join(f.Menu(), ", "))
+		}
+	}
+
+	piStore = pizzastore.CaliforniaStylePizzaStore()
+	fmt.Println("On the California menu:", strings.Join(piStore.Menu(), ", "))
+	order(piStore, "bbq chicken")
+	order(piStore, "veggie")
+	order(pizzastore.NewYorkStylePizzaStore(), "bbq chicken")
+
+	// Whole regions can come out of a file
+	if *profileFile != "" {
+		profile, err := ingredients.LoadProfile(*profileFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		f, err := ingredients.ProfileIngredientFactory(profile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if dough, ok := f.CreateDough().(ingredients.Attributed); ok && dough.Attribute("crust") != "" {
+			fmt.Printf("%s makes its pizzas on a %s crust\n", profile.Region, dough.Attribute("crust"))
+		}
+		pizzas.RegisterRegion(pizzas.Region{Name: profile.Region, Ingredients: f, Style: pizzas.ChicagoDeepDish})
+		pizzas.Register("pepperoni", func(r pizzas.Region) pizzas.Pizza {
+			return pizzas.Pepperoni(r.Name+" Pepperoni", r)
+		}, profile.Region)
+		factory, err := pizzas.RegionFactory(profile.Region)
+		if err != nil {
+			log.Fatal(err)
+		}
+		order(pizzastore.NewPizzaStore(factory), "pepperoni")
+	}
+}
+
+func order(s pizzastore.PizzaStore, pizzaType string) {
+	if _, err := s.OrderPizza(pizzaType); err != nil {
+		fmt.Println("Sorry:", err)
+	}
 }
@@ -0,0 +1,62 @@
+package pizzas
+
+import (
+	"fmt"
+	"strings"
+
+	"headfirstdesigntraining/factory/ingredients"
+)
+
+// california has no factory type of its own, it's made entirely out of the registry
+const california = "California"
+
+func CaliforniaStyleFactory() Factory {
+	return regionFactory{region: california}
+}
+
+func init() {
+	RegisterRegion(Region{Name: california, Ingredients: ingredients.CaliforniaIngredientFactory(), Style: CaliforniaThinCrust})
+	Register("cheese", cheeseRecipe, california)
+	Register("veggie", veggieRecipe, california)
+	// Only in California
+	Register(bbqChickenType, func(r Region) Pizza {
+		return BBQChicken(r.Name+" BBQ Chicken", r)
+	}, california)
+}
+
+// bbqChickenType is what BBQ chicken is ordered as, and which recipe in the region's ingredient
+// profile it's made with
+const bbqChickenType = "bbq chicken"
+
+// BBQChicken is made with the region's sauce and toppings for BBQ chicken, if its ingredients
+// have any
+func BBQChicken(name string, r Region) Pizza {
+	f := r.Ingredients
+	if rf, ok := f.(ingredients.RecipeIngredientFactory); ok {
+		f = rf.ForRecipe(bbqChickenType)
+	}
+	return &bbqChicken{ingredientFactory: f, name: name, baked: baked{r.Style}}
+}
+
+type bbqChicken struct{
+	baked
+	name string
+	ingredientFactory ingredients.PizzaIngredientFactory
+}
+
+func (p bbqChicken) Prepare() {
+	fmt.Println("Preparing", p.name)
+	d := p.ingredientFactory.CreateDough()
+	s := p.ingredientFactory.CreateSauce()
+	c := p.ingredientFactory.CreateCheese()
+	names := []string{d.Name(), s.Name(), c.Name()}
+	if rf, ok := p.ingredientFactory.(ingredients.RecipeIngredientFactory); ok {
+		for _, t := range rf.CreateToppings() {
+			names = append(names, t.Name())
+		}
+	}
+	for _, v := range p.ingredientFactory.CreateVeggies() {
+		names = append(names, v.Name())
+	}
+	fmt.Printf("Laying on the ingredients: %s\n", strings.Join(names, ", "))
+}
@@ -12,14 +12,27 @@ func ChicagoStyleFactory() Factory {
 
 type chicagoFactory struct {}
 
-func (c chicagoFactory) CreatePizza(pizzaType string) Pizza {
-	fmt.Printf("Preparing a delectable Chicago-style %s pizza\n", pizzaType)
-	f := ingredients.ChicagoIngredientFactory()
-	switch pizzaType {
-	case "cheese":
-		return Cheese("Chicago Cheese", f)
-	case "pepperoni":
-		return Pepperoni("Chicago Pepperoni", f)
+func init() {
+	chicago := chicagoFactory{}.Region()
+	RegisterRegion(Region{Name: chicago, Ingredients: ingredients.ChicagoIngredientFactory(), Style: ChicagoDeepDish})
+	Register("pepperoni", func(r Region) Pizza {
+		return Pepperoni("Chicago Pepperoni", r)
+	}, chicago)
+}
+
+func (c chicagoFactory) CreatePizza(pizzaType string) (Pizza, error) {
+	pizza, err := create(c.Region(), pizzaType)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	fmt.Printf("Preparing a delectable Chicago-style %s pizza\n", pizzaType)
+	return pizza, nil
+}
+
+func (c chicagoFactory) Menu() []string {
+	return menu(c.Region())
+}
+
+func (c chicagoFactory) Region() string {
+	return "Chicago"
 }
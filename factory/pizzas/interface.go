@@ -1,12 +1,48 @@
 package pizzas
 
+import "fmt"
+
 type Pizza interface {
 	Prepare()
-	Bake()
-	Cut()
-	Box()
+	Bake() Step
+	Cut() Step
+	Box() Step
 }
 
 type Factory interface {
-	CreatePizza(pizzaType string) Pizza
+	// CreatePizza makes a pizza off the menu, or returns an UnknownPizzaError or UnavailableError.
+	// A factory for a region that's no longer registered returns an UnknownRegionError.
+	CreatePizza(pizzaType string) (Pizza, error)
+	// Menu lists the pizza types the factory makes
+	Menu() []string
+	Region() string
+}
+
+// UnknownPizzaError is returned for a pizza type no region makes
+type UnknownPizzaError struct {
+	Type string
+}
+
+func (e *UnknownPizzaError) Error() string {
+	return fmt.Sprintf("unknown pizza type %q", e.Type)
+}
+
+// UnavailableError is returned for a pizza type that's made somewhere, just not in this region
+type UnavailableError struct {
+	Type   string
+	Region string
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s pizza isn't available in %s", e.Type, e.Region)
+}
+
+// UnknownRegionError is returned for a region that hasn't been registered, or has no ingredients
+// to make pizzas with
+type UnknownRegionError struct {
+	Region string
+}
+
+func (e *UnknownRegionError) Error() string {
+	return fmt.Sprintf("no pizzas are made in %s", e.Region)
 }
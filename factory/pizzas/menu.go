@@ -0,0 +1,81 @@
+package pizzas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Kinds are the pizzas a menu file can make, each of them out of whatever ingredients its region
+// has
+var Kinds = map[string]func(name string, r Region) Pizza{
+	"cheese":    Cheese,
+	"pepperoni": Pepperoni,
+	"clam":      Clam,
+	"veggie":    Veggie,
+}
+
+// MenuItem is a pizza as a menu file writes it down
+type MenuItem struct {
+	// Type is what the pizza's ordered as
+	Type string `json:"type"`
+	// Kind is which of the Kinds it's made as
+	Kind string `json:"kind"`
+	// Name is what it's called after the region's name, e.g. "Margherita" for "New York Margherita"
+	Name    string   `json:"name"`
+	Regions []string `json:"regions"`
+}
+
+// ReadMenu registers the pizzas in a JSON menu such as
+//
+//	[{"type": "margherita", "kind": "cheese", "name": "Margherita", "regions": ["New York"]}]
+//
+// Every region has to be registered already, or ReadMenu returns an UnknownRegionError. Nothing's
+// registered unless the whole menu makes sense.
+func ReadMenu(r io.Reader) error {
+	var items []MenuItem
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&items); err != nil {
+		return fmt.Errorf("reading pizza menu: %w", err)
+	}
+	for _, item := range items {
+		if item.Type == "" || item.Name == "" {
+			return fmt.Errorf("pizza menu: every pizza needs a type and a name")
+		}
+		if _, ok := Kinds[item.Kind]; !ok {
+			return fmt.Errorf("pizza menu: %s is an unknown kind of pizza %q", item.Type, item.Kind)
+		}
+		if len(item.Regions) == 0 {
+			return fmt.Errorf("pizza menu: %s isn't made in any region", item.Type)
+		}
+		// A misspelt region would otherwise quietly start a new one nobody orders from
+		for _, name := range item.Regions {
+			if _, err := RegionFactory(name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, item := range items {
+		newPizza, name := Kinds[item.Kind], item.Name
+		Register(item.Type, func(r Region) Pizza {
+			return newPizza(strings.TrimSpace(r.Name+" "+name), r)
+		}, item.Regions...)
+	}
+	return nil
+}
+
+// LoadMenu registers the pizzas in a JSON menu file, see ReadMenu
+func LoadMenu(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := ReadMenu(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -12,36 +12,35 @@ func NewYorkStyleFactory() Factory {
 
 type nyFactory struct {}
 
-func (c nyFactory) CreatePizza(pizzaType string) Pizza {
-	fmt.Printf("Preparing a classy NY-style %s pizza\n", pizzaType)
-	f := ingredients.NYIngredientFactory()
-	switch pizzaType {
-	case "cheese":
-		return nyCheese(f)
-	case "pepperoni":
-		return nyPepperoni{}
-	}
-	return nil
+func init() {
+	ny := nyFactory{}.Region()
+	RegisterRegion(Region{Name: ny, Ingredients: ingredients.NYIngredientFactory(), Style: NewYorkThinCrust})
+	Register("pepperoni", func(r Region) Pizza {
+		return nyPepperoni{baked{r.Style}}
+	}, ny)
 }
 
-func nyCheese(f ingredients.PizzaIngredientFactory) Pizza {
-	return Cheese("New York Cheese", f)
+func (c nyFactory) CreatePizza(pizzaType string) (Pizza, error) {
+	pizza, err := create(c.Region(), pizzaType)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Preparing a classy NY-style %s pizza\n", pizzaType)
+	return pizza, nil
 }
 
-type nyPepperoni struct{}
-
-func (p nyPepperoni) Prepare() {
-	fmt.Print("Slapping some dough...\nSpreading some sauce\nSprinkling some cheese\nPlacing some pepps\n")
+func (c nyFactory) Menu() []string {
+	return menu(c.Region())
 }
 
-func (p nyPepperoni) Bake() {
-	fmt.Print("Sliding this baby into the oven\n")
+func (c nyFactory) Region() string {
+	return "New York"
 }
 
-func (p nyPepperoni) Cut() {
-	fmt.Print("Cutting this sucker into quarters for your folding pleasure\n")
+type nyPepperoni struct{
+	baked
 }
 
-func (p nyPepperoni) Box() {
-	fmt.Print("Boxing 'er up\n")
+func (p nyPepperoni) Prepare() {
+	fmt.Print("Slapping some dough...\nSpreading some sauce\nSprinkling some cheese\nPlacing some pepps\n")
 }
@@ -2,15 +2,17 @@ package pizzas
 
 import (
 	"fmt"
+	"strings"
 
 	"headfirstdesigntraining/factory/ingredients"
 )
 
-func Pepperoni(name string, f ingredients.PizzaIngredientFactory) Pizza{
-	return &pepperoni{ingredientFactory: f, name: name}
+func Pepperoni(name string, r Region) Pizza{
+	return &pepperoni{ingredientFactory: r.Ingredients, name: name, baked: baked{r.Style}}
 }
 
 type pepperoni struct{
+	baked
 	name string
 	ingredientFactory ingredients.PizzaIngredientFactory
 }
@@ -26,20 +28,31 @@ func (p pepperoni) Prepare() {
 	fmt.Printf("Laying on the ingredients: %s, %s, %s, %s\n", d.Name(), s.Name(), c.Name(), pep.Name())
 }
 
-func (p pepperoni) Bake() {
+func init() {
+	// These are the same everywhere, it's only the ingredients that change
+	Register("cheese", cheeseRecipe, chicagoFactory{}.Region(), nyFactory{}.Region())
+	Register("clam", clamRecipe, chicagoFactory{}.Region(), nyFactory{}.Region())
+	Register("veggie", veggieRecipe, chicagoFactory{}.Region(), nyFactory{}.Region())
 }
 
-func (p pepperoni) Cut() {
+func cheeseRecipe(r Region) Pizza {
+	return Cheese(r.Name+" Cheese", r)
 }
 
-func (p pepperoni) Box() {
+func clamRecipe(r Region) Pizza {
+	return Clam(r.Name+" Clam", r)
 }
 
-func Cheese(name string, f ingredients.PizzaIngredientFactory) Pizza{
-	return &cheese{ingredientFactory: f, name: name}
+func veggieRecipe(r Region) Pizza {
+	return Veggie(r.Name+" Veggie", r)
+}
+
+func Cheese(name string, r Region) Pizza{
+	return &cheese{ingredientFactory: r.Ingredients, name: name, baked: baked{r.Style}}
 }
 
 type cheese struct{
+	baked
 	name string
 	ingredientFactory ingredients.PizzaIngredientFactory
 }
@@ -54,13 +67,43 @@ func (c cheese) Prepare() {
 	fmt.Printf("Laying on the ingredients: %s, %s, %s\n", d.Name(), s.Name(), ch.Name())
 }
 
-func (c cheese) Bake() {
+func Clam(name string, r Region) Pizza{
+	return &clam{ingredientFactory: r.Ingredients, name: name, baked: baked{r.Style}}
 }
 
-func (c cheese) Cut() {
+type clam struct{
+	baked
+	name string
+	ingredientFactory ingredients.PizzaIngredientFactory
+}
+
+func (c clam) Prepare() {
+	fmt.Println("Preparing", c.name)
+	d := c.ingredientFactory.CreateDough()
+	s := c.ingredientFactory.CreateSauce()
+	ch := c.ingredientFactory.CreateCheese()
+	cl := c.ingredientFactory.CreateClam()
+	fmt.Printf("Laying on the ingredients: %s, %s, %s, %s\n", d.Name(), s.Name(), ch.Name(), cl.Name())
 }
 
-func (c cheese) Box() {
+func Veggie(name string, r Region) Pizza{
+	return &veggie{ingredientFactory: r.Ingredients, name: name, baked: baked{r.Style}}
 }
 
+type veggie struct{
+	baked
+	name string
+	ingredientFactory ingredients.PizzaIngredientFactory
+}
 
+func (v veggie) Prepare() {
+	fmt.Println("Preparing", v.name)
+	d := v.ingredientFactory.CreateDough()
+	s := v.ingredientFactory.CreateSauce()
+	ch := v.ingredientFactory.CreateCheese()
+	names := []string{d.Name(), s.Name(), ch.Name()}
+	for _, veg := range v.ingredientFactory.CreateVeggies() {
+		names = append(names, veg.Name())
+	}
+	fmt.Printf("Laying on the ingredients: %s\n", strings.Join(names, ", "))
+}
@@ -0,0 +1,142 @@
+package pizzas
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"headfirstdesigntraining/factory/ingredients"
+)
+
+// Region is somewhere pizzas are made: the ingredients they're made of, and the style they're
+// baked in
+type Region struct {
+	Name        string
+	Ingredients ingredients.PizzaIngredientFactory
+	Style       Style
+}
+
+// Recipe makes a pizza the way a region does
+type Recipe func(r Region) Pizza
+
+type region struct {
+	Region
+	recipes map[string]Recipe
+}
+
+var (
+	registryMu sync.RWMutex
+	regions    = map[string]*region{}
+)
+
+// RegisterRegion adds a region, or changes how it makes its pizzas. Pizzas can be registered for
+// a region before or after the region itself is.
+func RegisterRegion(r Region) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	lookupRegion(r.Name).Region = r
+}
+
+// Register puts a pizza on the menu of one or more regions, replacing any recipe they already
+// had under that name
+func Register(pizzaType string, recipe Recipe, regionNames ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, name := range regionNames {
+		lookupRegion(name).recipes[pizzaType] = recipe
+	}
+}
+
+// Regions lists the regions that have ingredients to make pizzas with
+func Regions() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var names []string
+	for name, r := range regions {
+		if r.Ingredients != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegionFactory makes pizzas for any registered region, or returns an UnknownRegionError
+func RegionFactory(name string) (Factory, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if r, ok := regions[name]; !ok || r.Ingredients == nil {
+		return nil, &UnknownRegionError{Region: name}
+	}
+	return regionFactory{region: name}, nil
+}
+
+type regionFactory struct {
+	region string
+}
+
+func (f regionFactory) CreatePizza(pizzaType string) (Pizza, error) {
+	pizza, err := create(f.region, pizzaType)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Preparing a %s-style %s pizza\n", f.region, pizzaType)
+	return pizza, nil
+}
+
+func (f regionFactory) Menu() []string {
+	return menu(f.region)
+}
+
+func (f regionFactory) Region() string {
+	return f.region
+}
+
+// lookupRegion finds a region, adding it if it's new. registryMu has to be held to write.
+func lookupRegion(name string) *region {
+	r, ok := regions[name]
+	if !ok {
+		r = &region{Region: Region{Name: name}, recipes: map[string]Recipe{}}
+		regions[name] = r
+	}
+	return r
+}
+
+// create makes a pizza off a region's menu
+func create(regionName, pizzaType string) (Pizza, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := regions[regionName]
+	if !ok || r.Ingredients == nil {
+		return nil, &UnknownRegionError{Region: regionName}
+	}
+	recipe, ok := r.recipes[pizzaType]
+	if !ok {
+		return nil, notOnMenu(regionName, pizzaType)
+	}
+	return recipe(r.Region), nil
+}
+
+// menu lists the pizza types a region makes
+func menu(regionName string) []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var types []string
+	if r, ok := regions[regionName]; ok {
+		for t := range r.recipes {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// notOnMenu works out why a region can't make a pizza. registryMu has to be held.
+func notOnMenu(region, pizzaType string) error {
+	for _, r := range regions {
+		if _, ok := r.recipes[pizzaType]; ok {
+			return &UnavailableError{Type: pizzaType, Region: region}
+		}
+	}
+	return &UnknownPizzaError{Type: pizzaType}
+}
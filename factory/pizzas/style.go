@@ -0,0 +1,92 @@
+package pizzas
+
+import (
+	"fmt"
+	"time"
+)
+
+type CutPattern int
+
+const (
+	// Wedges is the classic triangle slice
+	Wedges CutPattern = iota
+	// Squares cuts the pie into a grid of big square slices
+	Squares
+	// PartyCut cuts a thin pie into a grid of small squares, tavern style
+	PartyCut
+)
+
+func (c CutPattern) String() string {
+	switch c {
+	case Wedges:
+		return "wedges"
+	case Squares:
+		return "squares"
+	case PartyCut:
+		return "party cut"
+	}
+	return fmt.Sprintf("CutPattern(%d)", int(c))
+}
+
+// Style is how a region bakes, cuts and boxes its pizzas
+type Style struct {
+	Name     string
+	BakeTemp int // °F
+	BakeTime time.Duration
+	Cut      CutPattern
+	Slices   int
+	BoxSize  int // inches
+}
+
+var (
+	// ChicagoDeepDish sits in a cooler oven for a good while, and comes out in squares
+	ChicagoDeepDish = Style{Name: "Chicago deep dish", BakeTemp: 425, BakeTime: 35 * time.Minute, Cut: Squares, Slices: 9, BoxSize: 14}
+	// NewYorkThinCrust is in and out of a hot oven, and cut wide for folding
+	NewYorkThinCrust = Style{Name: "New York thin crust", BakeTemp: 550, BakeTime: 12 * time.Minute, Cut: Wedges, Slices: 8, BoxSize: 18}
+	// CaliforniaThinCrust is crisp and small, and party cut for sharing
+	CaliforniaThinCrust = Style{Name: "California thin crust", BakeTemp: 500, BakeTime: 10 * time.Minute, Cut: PartyCut, Slices: 16, BoxSize: 12}
+)
+
+// Step is how one stage of making a pizza went, for the store to log or time
+type Step struct {
+	Name     string // "bake", "cut" or "box"
+	Duration time.Duration
+	// What the step did, depending on the step
+	Temp    int // °F
+	Cut     CutPattern
+	Slices  int
+	BoxSize int // inches
+}
+
+func (s Step) String() string {
+	switch s.Name {
+	case "bake":
+		return fmt.Sprintf("baked for %s at %d°F", s.Duration, s.Temp)
+	case "cut":
+		if s.Cut == PartyCut {
+			return fmt.Sprintf("party cut into %d squares", s.Slices)
+		}
+		return fmt.Sprintf("cut into %d %s", s.Slices, s.Cut)
+	case "box":
+		return fmt.Sprintf("boxed (%d\" box)", s.BoxSize)
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.Duration)
+}
+
+// baked gives every pizza its Bake, Cut and Box, the way its style says to
+type baked struct {
+	style Style
+}
+
+func (b baked) Bake() Step {
+	return Step{Name: "bake", Duration: b.style.BakeTime, Temp: b.style.BakeTemp}
+}
+
+func (b baked) Cut() Step {
+	// Every cut's a minute, give or take
+	return Step{Name: "cut", Duration: time.Minute, Cut: b.style.Cut, Slices: b.style.Slices}
+}
+
+func (b baked) Box() Step {
+	return Step{Name: "box", Duration: 30 * time.Second, BoxSize: b.style.BoxSize}
+}
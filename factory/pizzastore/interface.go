@@ -5,6 +5,10 @@ import (
 )
 
 type PizzaStore interface {
-	OrderPizza(pizzaType string) pizzas.Pizza
-	CreatePizza(pizzaType string) pizzas.Pizza
+	// OrderPizza makes a pizza start to finish. Pizzas that aren't on the menu come back as a
+	// pizzas.UnknownPizzaError or pizzas.UnavailableError.
+	OrderPizza(pizzaType string) (pizzas.Pizza, error)
+	CreatePizza(pizzaType string) (pizzas.Pizza, error)
+	// Menu lists the pizza types the store sells
+	Menu() []string
 }
@@ -1,6 +1,9 @@
 package pizzastore
 
 import (
+	"fmt"
+	"time"
+
 	"headfirstdesigntraining/factory/pizzas"
 )
 
@@ -14,15 +17,16 @@ type pizzaStore struct{
 	pizzas.Factory
 }
 
-func (p pizzaStore) OrderPizza(pizzaType string) pizzas.Pizza {
-	pizza := p.CreatePizza(pizzaType)
+func (p pizzaStore) OrderPizza(pizzaType string) (pizzas.Pizza, error) {
+	pizza, err := p.CreatePizza(pizzaType)
+	if err != nil {
+		return nil, err
+	}
 
 	pizza.Prepare()
-	pizza.Bake()
-	pizza.Cut()
-	pizza.Box()
+	finish(pizza)
 
-	return pizza
+	return pizza, nil
 }
 
 func ChicagoStylePizzaStore() PizzaStore {
@@ -35,15 +39,16 @@ type chicagoStylePizzaStore struct{
 	pizzas.Factory
 }
 
-func (p chicagoStylePizzaStore) OrderPizza(pizzaType string) pizzas.Pizza {
-	pizza := p.CreatePizza(pizzaType)
+func (p chicagoStylePizzaStore) OrderPizza(pizzaType string) (pizzas.Pizza, error) {
+	pizza, err := p.CreatePizza(pizzaType)
+	if err != nil {
+		return nil, err
+	}
 
 	pizza.Prepare()
-	pizza.Bake()
-	pizza.Cut()
-	pizza.Box()
+	finish(pizza)
 
-	return pizza
+	return pizza, nil
 }
 
 func NewYorkStylePizzaStore() PizzaStore {
@@ -56,13 +61,29 @@ type nyStylePizzaStore struct{
 	pizzas.Factory
 }
 
-func (p nyStylePizzaStore) OrderPizza(pizzaType string) pizzas.Pizza {
-	pizza := p.CreatePizza(pizzaType)
+func (p nyStylePizzaStore) OrderPizza(pizzaType string) (pizzas.Pizza, error) {
+	pizza, err := p.CreatePizza(pizzaType)
+	if err != nil {
+		return nil, err
+	}
 
 	pizza.Prepare()
-	pizza.Bake()
-	pizza.Cut()
-	pizza.Box()
+	finish(pizza)
 
-	return pizza
+	return pizza, nil
+}
+
+func CaliforniaStylePizzaStore() PizzaStore {
+	return NewPizzaStore(pizzas.CaliforniaStyleFactory())
+}
+
+// finish bakes, cuts and boxes a prepared pizza, logging each step and how long it all took
+func finish(pizza pizzas.Pizza) {
+	steps := []pizzas.Step{pizza.Bake(), pizza.Cut(), pizza.Box()}
+	var took time.Duration
+	for _, s := range steps {
+		fmt.Println("  ...", s)
+		took += s.Duration
+	}
+	fmt.Println("Ready in", took)
 }